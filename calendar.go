@@ -0,0 +1,351 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Define the offsets from the epochs of the timestamps to the unix epoch.
+const (
+	// julianDayOfUnixEpoch is the Julian day number at the noon of 1970-01-01.
+	julianDayOfUnixEpoch = 2440588
+
+	// ntpEpochOffset is the seconds from 1900-01-01 to 1970-01-01.
+	ntpEpochOffset = 2208988800
+)
+
+// tickEpoch represents the timestamp counting the ticks since an epoch.
+type tickEpoch struct {
+	offset int64 // The seconds from the epoch to the unix epoch.
+	tick   int64 // The nanoseconds of a tick.
+}
+
+var (
+	// fileTimeEpoch is the epoch of Windows FILETIME,
+	// that's, the 100ns ticks since 1601-01-01 UTC.
+	fileTimeEpoch = tickEpoch{offset: 11644473600, tick: 100}
+
+	// dotNetEpoch is the epoch of .NET DateTime.Ticks,
+	// that's, the 100ns ticks since 0001-01-01 UTC.
+	dotNetEpoch = tickEpoch{offset: 62135596800, tick: 100}
+)
+
+func (e tickEpoch) toTime(ticks int64) time.Time {
+	n := int64(time.Second) / e.tick
+	return time.Unix(ticks/n-e.offset, ticks%n*e.tick)
+}
+
+// FromExcelSerial is equal to DefaultConverter.FromExcelSerial(any, loc, date1904).
+func FromExcelSerial(any interface{}, loc *time.Location, date1904 bool) (time.Time, error) {
+	return DefaultConverter.FromExcelSerial(any, loc, date1904)
+}
+
+// ToExcelSerial is equal to DefaultConverter.ToExcelSerial(any, loc, date1904).
+func ToExcelSerial(any interface{}, loc *time.Location, date1904 bool) (float64, error) {
+	return DefaultConverter.ToExcelSerial(any, loc, date1904)
+}
+
+// FromJulianDay is equal to DefaultConverter.FromJulianDay(any, loc).
+func FromJulianDay(any interface{}, loc *time.Location) (time.Time, error) {
+	return DefaultConverter.FromJulianDay(any, loc)
+}
+
+// ToJulianDay is equal to DefaultConverter.ToJulianDay(any).
+func ToJulianDay(any interface{}) (float64, error) { return DefaultConverter.ToJulianDay(any) }
+
+// FromFileTime is equal to DefaultConverter.FromFileTime(any, loc).
+func FromFileTime(any interface{}, loc *time.Location) (time.Time, error) {
+	return DefaultConverter.FromFileTime(any, loc)
+}
+
+// ToFileTime is equal to DefaultConverter.ToFileTime(any).
+func ToFileTime(any interface{}) (int64, error) { return DefaultConverter.ToFileTime(any) }
+
+// FromNTP is equal to DefaultConverter.FromNTP(any, loc).
+func FromNTP(any interface{}, loc *time.Location) (time.Time, error) {
+	return DefaultConverter.FromNTP(any, loc)
+}
+
+// ToNTP is equal to DefaultConverter.ToNTP(any).
+func ToNTP(any interface{}) (uint64, error) { return DefaultConverter.ToNTP(any) }
+
+// FromDotNetTicks is equal to DefaultConverter.FromDotNetTicks(any, loc).
+func FromDotNetTicks(any interface{}, loc *time.Location) (time.Time, error) {
+	return DefaultConverter.FromDotNetTicks(any, loc)
+}
+
+// ToDotNetTicks is equal to DefaultConverter.ToDotNetTicks(any).
+func ToDotNetTicks(any interface{}) (int64, error) { return DefaultConverter.ToDotNetTicks(any) }
+
+// FromExcelSerial converts the Excel serial date, such as 45000.5 or "45000.5",
+// which is the days with the fractional time of day, to time.Time in loc.
+//
+// If date1904 is false, use the 1900 date system, in which 1 is 1900-01-01
+// and 1900 is considered as a leap year like Lotus 1-2-3, so the serials
+// from 61 are one day more than the actual days, and 60, the nonexistent
+// 1900-02-29, is converted to 1900-03-01. Or, use the 1904 date system,
+// in which 0 is 1904-01-01.
+//
+// The serial date is the wall clock, so the result is in loc, and
+// if loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) FromExcelSerial(any interface{}, loc *time.Location, date1904 bool) (time.Time, error) {
+	loc = c.location(loc)
+	days, ns, err := c.toDays("FromExcelSerial", any)
+	if err != nil {
+		return time.Time{}.In(loc), err
+	}
+
+	year, month, day := 1899, time.December, 30
+	switch {
+	case date1904:
+		year, month, day = 1904, time.January, 1
+	case days < 61:
+		day = 31
+	}
+
+	sec, nsec := int(ns/int64(time.Second)), int(ns%int64(time.Second))
+	return time.Date(year, month, day+int(days), 0, 0, sec, nsec, loc), nil
+}
+
+// ToExcelSerial converts any to time.Time in loc by ToTimeInLocation,
+// and returns the Excel serial date of its wall clock.
+//
+// See FromExcelSerial.
+func (c *Converter) ToExcelSerial(any interface{}, loc *time.Location, date1904 bool) (float64, error) {
+	t, err := c.ToTimeInLocation(any, loc)
+	if err != nil {
+		return 0, err
+	}
+
+	days := civilDays(t.Date())
+	switch {
+	case date1904:
+		days -= civilDays(1904, time.January, 1)
+	case days < civilDays(1900, time.March, 1):
+		days -= civilDays(1899, time.December, 31)
+	default:
+		days -= civilDays(1899, time.December, 30)
+	}
+
+	hour, min, sec := t.Clock()
+	ns := time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	return float64(days) + float64(ns)/float64(24*time.Hour), nil
+}
+
+// civilDays returns the days from 1970-01-01 to the date.
+func civilDays(year int, month time.Month, day int) int64 {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// FromJulianDay converts the astronomical Julian day, such as 2460263.5
+// or "2460263.5", which is the days since the noon of 4713-11-24 BC UTC
+// in the proleptic Gregorian calendar, to time.Time in loc.
+//
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) FromJulianDay(any interface{}, loc *time.Location) (time.Time, error) {
+	loc = c.location(loc)
+	days, ns, err := c.toDays("FromJulianDay", any)
+	if err != nil {
+		return time.Time{}.In(loc), err
+	}
+
+	sec := (days-julianDayOfUnixEpoch)*86400 + 43200
+	return time.Unix(sec, ns).In(loc), nil
+}
+
+// ToJulianDay converts any to time.Time by ToTime,
+// and returns the astronomical Julian day.
+//
+// See FromJulianDay.
+func (c *Converter) ToJulianDay(any interface{}) (float64, error) {
+	t, err := c.ToTime(any)
+	if err != nil {
+		return 0, err
+	}
+
+	// Count from the noon of 1969-12-31, whose Julian day is 2440587.
+	sec := t.Unix() + 43200
+	days, sec := sec/86400, sec%86400
+	if sec < 0 {
+		days, sec = days-1, sec+86400
+	}
+
+	ns := sec*int64(time.Second) + int64(t.Nanosecond())
+	return float64(days+julianDayOfUnixEpoch-1) + float64(ns)/float64(24*time.Hour), nil
+}
+
+// FromFileTime converts Windows FILETIME, that's, the 100ns ticks
+// since 1601-01-01 UTC, to time.Time in loc.
+//
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) FromFileTime(any interface{}, loc *time.Location) (time.Time, error) {
+	return c.fromTicks(any, loc, fileTimeEpoch)
+}
+
+// ToFileTime converts any to time.Time by ToTime, and returns Windows
+// FILETIME, which rounds the nanoseconds by the rounding mode.
+//
+// See FromFileTime.
+func (c *Converter) ToFileTime(any interface{}) (int64, error) {
+	return c.toTicks(any, fileTimeEpoch)
+}
+
+// FromDotNetTicks converts .NET DateTime.Ticks, that's, the 100ns ticks
+// since 0001-01-01 UTC, to time.Time in loc.
+//
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) FromDotNetTicks(any interface{}, loc *time.Location) (time.Time, error) {
+	return c.fromTicks(any, loc, dotNetEpoch)
+}
+
+// ToDotNetTicks converts any to time.Time by ToTime, and returns .NET
+// DateTime.Ticks, which rounds the nanoseconds by the rounding mode.
+//
+// See FromDotNetTicks.
+func (c *Converter) ToDotNetTicks(any interface{}) (int64, error) {
+	return c.toTicks(any, dotNetEpoch)
+}
+
+func (c *Converter) fromTicks(any interface{}, loc *time.Location, e tickEpoch) (time.Time, error) {
+	loc = c.location(loc)
+	ticks, err := c.ToInt64(any)
+	if err != nil {
+		return time.Time{}.In(loc), err
+	}
+	return e.toTime(ticks).In(loc), nil
+}
+
+func (c *Converter) toTicks(any interface{}, e tickEpoch) (int64, error) {
+	t, err := c.ToTime(any)
+	if err != nil {
+		return 0, err
+	}
+
+	// Let the seconds and nanoseconds have the same sign to round.
+	sec, nsec := t.Unix()+e.offset, int64(t.Nanosecond())
+	if sec < 0 && nsec > 0 {
+		sec, nsec = sec+1, nsec-int64(time.Second)
+	}
+
+	if c.Lossless && nsec%e.tick != 0 {
+		return 0, precisionLossError(any, int64Type)
+	}
+
+	n := int64(time.Second) / e.tick
+	if sec > math.MaxInt64/n || sec < math.MinInt64/n {
+		return c.overflowInt(any, int64Type, sec > 0, math.MinInt64, math.MaxInt64, sec*n)
+	}
+
+	ticks := c.Rounding.roundFraction(nsec/e.tick, float64(nsec%e.tick)/float64(e.tick))
+	return sec*n + ticks, nil
+}
+
+// FromNTP converts the NTP 64-bit timestamp, whose high 32 bits are
+// the seconds and low 32 bits are the fraction of a second, to time.Time
+// in loc, which rounds the nanoseconds by the rounding mode.
+//
+// Like RFC 4330, if the most significant bit is set, the seconds are
+// since 1900-01-01 UTC, or since 2036-02-07 06:28:16 UTC, so it covers
+// the time from 1968 to 2104. And 0, meaning the unknown time,
+// is converted to the zero time.
+//
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) FromNTP(any interface{}, loc *time.Location) (time.Time, error) {
+	loc = c.location(loc)
+	v, err := c.ToUint64(any)
+	if err != nil || v == 0 {
+		return time.Time{}.In(loc), err
+	}
+
+	sec, frac := int64(v>>32), int64(v&(1<<32-1))
+	if sec < 1<<31 {
+		sec += 1 << 32
+	}
+
+	frac *= int64(time.Second)
+	ns := c.Rounding.roundFraction(frac>>32, float64(frac&(1<<32-1))/(1<<32))
+	return time.Unix(sec-ntpEpochOffset, ns).In(loc), nil
+}
+
+// ToNTP converts any to time.Time by ToTime, and returns the NTP 64-bit
+// timestamp, which rounds the fraction of a second by the rounding mode.
+//
+// If the time is not in the range from 1968 to 2104, return an error
+// with ErrOverflow. See FromNTP.
+func (c *Converter) ToNTP(any interface{}) (uint64, error) {
+	t, err := c.ToTime(any)
+	if err != nil || t.IsZero() {
+		return 0, err
+	}
+
+	sec := t.Unix() + ntpEpochOffset
+	if sec < 1<<31 || sec >= 1<<32+1<<31 {
+		return 0, newError("ToNTP", any, uint64Type, ErrOverflow, nil)
+	}
+
+	ns := int64(t.Nanosecond()) << 32
+	frac := c.Rounding.roundFraction(ns/int64(time.Second), float64(ns%int64(time.Second))/float64(time.Second))
+	return uint64(sec)<<32 + uint64(frac), nil
+}
+
+// toDays converts any, such as 45000.5 or "45000.5", to the days and the
+// nanoseconds of the fractional day, which have the same sign.
+//
+// The float is formatted to the shortest decimal string to be parsed,
+// so that the fraction is not affected by its binary error.
+func (c *Converter) toDays(op string, any interface{}) (days, ns int64, err error) {
+	var s string
+	switch v := any.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+
+	if s == "" || c.Strict {
+		var f float64
+		if f, err = c.ToFloat64(any); err != nil {
+			return
+		} else if math.IsNaN(f) || math.IsInf(f, 0) {
+			err = newError(op, any, timeType, ErrOverflow, nil)
+			return
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	if !isIntegerString(s) && !isDecimalString(s) {
+		err = newError(op, any, timeType, ErrSyntax, nil)
+		return
+	}
+
+	intpart, frac, _ := strings.Cut(s, ".")
+	if days, err = strconv.ParseInt(intpart, 10, 64); err != nil {
+		err = wrapError(op, any, timeType, err)
+		return
+	} else if days > math.MaxInt32 || days < math.MinInt32 {
+		err = newError(op, any, timeType, ErrOverflow, nil)
+		return
+	}
+
+	ns, err = c.scaleFraction(any, frac, int64(24*time.Hour), intpart[0] == '-')
+	return
+}
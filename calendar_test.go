@@ -0,0 +1,250 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ExampleFromExcelSerial() {
+	fmt.Println(FromExcelSerial(45000.5, time.UTC, false))
+	fmt.Println(FromExcelSerial("43538.25", time.UTC, true))
+	fmt.Println(ToExcelSerial("2023-03-15T12:00:00Z", time.UTC, false))
+
+	// Output:
+	// 2023-03-15 12:00:00 +0000 UTC <nil>
+	// 2023-03-15 06:00:00 +0000 UTC <nil>
+	// 45000.5 <nil>
+}
+
+func ExampleFromJulianDay() {
+	fmt.Println(FromJulianDay(2451545.0, time.UTC))
+	fmt.Println(ToJulianDay(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Output:
+	// 2000-01-01 12:00:00 +0000 UTC <nil>
+	// 2.4405875e+06 <nil>
+}
+
+func ExampleFromFileTime() {
+	fmt.Println(FromFileTime(int64(133444736001234567), time.UTC))
+	fmt.Println(ToFileTime(time.Unix(0, 0)))
+	fmt.Println(FromDotNetTicks(int64(638355968000000000), time.UTC))
+	fmt.Println(ToDotNetTicks(time.Unix(0, 0)))
+
+	// Output:
+	// 2023-11-14 22:13:20.1234567 +0000 UTC <nil>
+	// 116444736000000000 <nil>
+	// 2023-11-14 22:13:20 +0000 UTC <nil>
+	// 621355968000000000 <nil>
+}
+
+func ExampleFromNTP() {
+	fmt.Println(FromNTP(uint64(0xe8fe6f8080000000), time.UTC))
+	fmt.Println(FromNTP(uint64(0x0000000100000000), time.UTC))
+	fmt.Printf("%x\n", Must(ToNTP(time.Date(2023, 11, 14, 22, 13, 20, 500000000, time.UTC))))
+
+	// Output:
+	// 2023-11-14 22:13:20.5 +0000 UTC <nil>
+	// 2036-02-07 06:28:17 +0000 UTC <nil>
+	// e8fe6f8080000000
+}
+
+func TestExcelSerial(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		serial   float64
+		date1904 bool
+		expect   time.Time
+	}{
+		{0, false, time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{1, false, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{59, false, time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{61, false, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{45000.123, false, time.Date(2023, 3, 15, 2, 57, 7, 200000000, time.UTC)},
+		{45000.75, false, time.Date(2023, 3, 15, 18, 0, 0, 0, shanghai)},
+		{2958465, false, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{-0.5, false, time.Date(1899, 12, 30, 12, 0, 0, 0, time.UTC)},
+		{0, true, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{43538.5, true, time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		loc := test.expect.Location()
+		if v, err := FromExcelSerial(test.serial, loc, test.date1904); err != nil {
+			t.Errorf("%v: %v", test.serial, err)
+		} else if !v.Equal(test.expect) || v.Location() != loc {
+			t.Errorf("%v: expect %s, but got %s", test.serial, test.expect, v)
+		}
+
+		if v, err := ToExcelSerial(test.expect, loc, test.date1904); err != nil {
+			t.Errorf("%s: %v", test.expect, err)
+		} else if math.Abs(v-test.serial) > 1e-9 {
+			t.Errorf("%s: expect %v, but got %v", test.expect, test.serial, v)
+		}
+	}
+
+	if v, err := FromExcelSerial(60, time.UTC, false); err != nil {
+		t.Error(err)
+	} else if expect := time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC); !v.Equal(expect) {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+
+	if _, err := FromExcelSerial("abc", time.UTC, false); !errors.Is(err, ErrSyntax) {
+		t.Errorf("expect ErrSyntax, but got %v", err)
+	}
+	if _, err := FromExcelSerial(1e20, time.UTC, false); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+	if _, err := FromExcelSerial(math.NaN(), time.UTC, false); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+	if _, err := NewConverter(WithStrict(true)).FromExcelSerial("1", time.UTC, false); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect ErrUnsupported, but got %v", err)
+	}
+}
+
+func TestJulianDay(t *testing.T) {
+	tests := []struct {
+		jd     float64
+		expect time.Time
+	}{
+		{2440587.5, time.Unix(0, 0)},
+		{2451545, time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{2460263.25, time.Date(2023, 11, 14, 18, 0, 0, 0, time.UTC)},
+		{0, time.Date(-4713, 11, 24, 12, 0, 0, 0, time.UTC)},
+		{-0.5, time.Date(-4713, 11, 24, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if v, err := FromJulianDay(test.jd, time.UTC); err != nil {
+			t.Errorf("%v: %v", test.jd, err)
+		} else if !v.Equal(test.expect) {
+			t.Errorf("%v: expect %s, but got %s", test.jd, test.expect, v)
+		}
+
+		if v, err := ToJulianDay(test.expect); err != nil {
+			t.Errorf("%s: %v", test.expect, err)
+		} else if v != test.jd {
+			t.Errorf("%s: expect %v, but got %v", test.expect, test.jd, v)
+		}
+	}
+
+	if v, err := FromJulianDay("2451545", time.FixedZone("CST", 8*3600)); err != nil {
+		t.Error(err)
+	} else if s := v.Format(time.DateTime); s != "2000-01-01 20:00:00" {
+		t.Errorf("expect '%s', but got '%s'", "2000-01-01 20:00:00", s)
+	}
+}
+
+func TestTicks(t *testing.T) {
+	tests := []struct {
+		from   func(interface{}, *time.Location) (time.Time, error)
+		to     func(interface{}) (int64, error)
+		ticks  int64
+		expect time.Time
+	}{
+		{FromFileTime, ToFileTime, 0, time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{FromFileTime, ToFileTime, 116444736000000001, time.Unix(0, 100)},
+		{FromFileTime, ToFileTime, 116444735999999999, time.Unix(0, -100)},
+		{FromFileTime, ToFileTime, -1, time.Date(1600, 12, 31, 23, 59, 59, 999999900, time.UTC)},
+		{FromDotNetTicks, ToDotNetTicks, 0, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{FromDotNetTicks, ToDotNetTicks, 3155378975999999999, time.Date(9999, 12, 31, 23, 59, 59, 999999900, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if v, err := test.from(test.ticks, nil); err != nil {
+			t.Errorf("%d: %v", test.ticks, err)
+		} else if !v.Equal(test.expect) {
+			t.Errorf("%d: expect %s, but got %s", test.ticks, test.expect, v)
+		}
+
+		if v, err := test.to(test.expect); err != nil {
+			t.Errorf("%s: %v", test.expect, err)
+		} else if v != test.ticks {
+			t.Errorf("%s: expect %d, but got %d", test.expect, test.ticks, v)
+		}
+	}
+
+	tm := time.Unix(0, 150)
+	if v, _ := ToFileTime(tm); v != 116444736000000001 {
+		t.Errorf("expect %d, but got %d", 116444736000000001, v)
+	}
+	if v, _ := NewConverter(WithRounding(RoundHalfEven)).ToFileTime(tm); v != 116444736000000002 {
+		t.Errorf("expect %d, but got %d", 116444736000000002, v)
+	}
+	if v, _ := NewConverter(WithRounding(RoundFloor)).ToFileTime(time.Unix(-11644473600, -50)); v != -1 {
+		t.Errorf("expect %d, but got %d", -1, v)
+	}
+	if _, err := NewConverter(WithLossless(true)).ToFileTime(tm); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("expect ErrPrecisionLoss, but got %v", err)
+	}
+	if _, err := ToDotNetTicks(time.Date(40000, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+}
+
+func TestNTP(t *testing.T) {
+	tests := []struct {
+		ntp    uint64
+		expect time.Time
+	}{
+		{0x83aa7e80 << 32, time.Unix(0, 0)},
+		{0x80000000 << 32, time.Date(1968, 1, 20, 3, 14, 8, 0, time.UTC)},
+		{0xffffffff<<32 | 0x80000000, time.Date(2036, 2, 7, 6, 28, 15, 500000000, time.UTC)},
+		{0x7fffffff << 32, time.Date(2104, 2, 26, 9, 42, 23, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if v, err := FromNTP(test.ntp, nil); err != nil {
+			t.Errorf("%x: %v", test.ntp, err)
+		} else if !v.Equal(test.expect) {
+			t.Errorf("%x: expect %s, but got %s", test.ntp, test.expect, v)
+		}
+
+		if v, err := ToNTP(test.expect); err != nil {
+			t.Errorf("%s: %v", test.expect, err)
+		} else if v != test.ntp {
+			t.Errorf("%s: expect %x, but got %x", test.expect, test.ntp, v)
+		}
+	}
+
+	if v, err := FromNTP(0, nil); err != nil || !v.IsZero() {
+		t.Errorf("expect the zero time, but got %s, %v", v, err)
+	}
+	if v, err := ToNTP(time.Time{}); err != nil || v != 0 {
+		t.Errorf("expect 0, but got %d, %v", v, err)
+	}
+
+	for ns := int64(0); ns < 1000; ns++ {
+		tm := time.Unix(1700000000, ns*999999)
+		if v, err := NewConverter(WithRounding(RoundHalfUp)).ToNTP(tm); err != nil {
+			t.Fatal(err)
+		} else if v, _ := NewConverter(WithRounding(RoundHalfUp)).FromNTP(v, nil); !v.Equal(tm) {
+			t.Fatalf("expect %s, but got %s", tm, v)
+		}
+	}
+
+	if _, err := ToNTP(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+	if _, err := FromNTP(-1, nil); !errors.Is(err, ErrNegative) {
+		t.Errorf("expect ErrNegative, but got %v", err)
+	}
+}
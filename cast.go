@@ -17,14 +17,17 @@ package cast
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"time"
-
-	"github.com/xgfone/go-defaults"
 )
 
-// Define some hook functions to intercept the ToXXX conversion.
+// Define some hook functions to intercept the ToXXX conversion,
+// which are only used by DefaultConverter and the converters derived
+// from it by Clone or With if their own hook is nil.
+//
+// Deprecated: Use the hook fields of DefaultConverter or a new Converter instead.
 var (
 	ToBoolHook     func(src interface{}) (dst bool, err error)
 	ToInt64Hook    func(src interface{}) (dst int64, err error)
@@ -35,84 +38,39 @@ var (
 	ToTimeHook     func(src interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error)
 )
 
-// ToBool prefers to use ToBoolHook to convert any to a bool value
-// rather than ToBoolPure.
+// ToBool is equal to DefaultConverter.ToBool(any).
 func ToBool(any interface{}) (dst bool, err error) {
-	if ToBoolHook != nil {
-		dst, err = ToBoolHook(any)
-	} else {
-		dst, err = ToBoolPure(any)
-	}
-	return
+	return DefaultConverter.ToBool(any)
 }
 
-// ToInt64 prefers to use ToInt64Hook to convert any to a int64 value
-// rather than ToInt64Pure.
+// ToInt64 is equal to DefaultConverter.ToInt64(any).
 func ToInt64(any interface{}) (dst int64, err error) {
-	if ToInt64Hook != nil {
-		dst, err = ToInt64Hook(any)
-	} else {
-		dst, err = ToInt64Pure(any)
-	}
-	return
+	return DefaultConverter.ToInt64(any)
 }
 
-// ToUint64 prefers to use ToUint64Hook to convert any to a uint64 value
-// rather than ToUint64Pure.
+// ToUint64 is equal to DefaultConverter.ToUint64(any).
 func ToUint64(any interface{}) (dst uint64, err error) {
-	if ToUint64Hook != nil {
-		dst, err = ToUint64Hook(any)
-	} else {
-		dst, err = ToUint64Pure(any)
-	}
-	return
+	return DefaultConverter.ToUint64(any)
 }
 
-// ToFloat64 prefers to use ToFloat64Hook to convert any to a float64 value
-// rather than ToFloat64Pure.
+// ToFloat64 is equal to DefaultConverter.ToFloat64(any).
 func ToFloat64(any interface{}) (dst float64, err error) {
-	if ToFloat64Hook != nil {
-		dst, err = ToFloat64Hook(any)
-	} else {
-		dst, err = ToFloat64Pure(any)
-	}
-	return
+	return DefaultConverter.ToFloat64(any)
 }
 
-// ToString prefers to use ToStringHook to convert any to a string value
-// rather than ToStringPure.
+// ToString is equal to DefaultConverter.ToString(any).
 func ToString(any interface{}) (dst string, err error) {
-	if ToStringHook != nil {
-		dst, err = ToStringHook(any)
-	} else {
-		dst, err = ToStringPure(any)
-	}
-	return
+	return DefaultConverter.ToString(any)
 }
 
-// ToDuration prefers to use ToDurationHook to convert any to a time.Duration value
-// rather than ToDurationPure.
+// ToDuration is equal to DefaultConverter.ToDuration(any).
 func ToDuration(any interface{}) (dst time.Duration, err error) {
-	if ToDurationHook != nil {
-		dst, err = ToDurationHook(any)
-	} else {
-		dst, err = ToDurationPure(any)
-	}
-	return
+	return DefaultConverter.ToDuration(any)
 }
 
-// ToTimeInLocation prefers to use ToTimeHook to convert any to a time.Time value
-// rather than ToTimeInLocationPure.
+// ToTimeInLocation is equal to DefaultConverter.ToTimeInLocation(any, loc, layouts...).
 func ToTimeInLocation(any interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error) {
-	if ToTimeHook != nil {
-		if len(layouts) == 0 {
-			layouts = defaults.TimeFormats.Get()
-		}
-		dst, err = ToTimeHook(any, loc, layouts...)
-	} else {
-		dst, err = ToTimeInLocationPure(any, loc, layouts...)
-	}
-	return
+	return DefaultConverter.ToTimeInLocation(any, loc, layouts...)
 }
 
 // ToTime is a convenient function, which is equal to ToTimeInLocation(any, nil).
@@ -120,6 +78,46 @@ func ToTime(any interface{}) (dst time.Time, err error) {
 	return ToTimeInLocation(any, nil)
 }
 
+// ToBoolPure is equal to DefaultConverter.ToBoolPure(any).
+func ToBoolPure(any interface{}) (dst bool, err error) {
+	return DefaultConverter.ToBoolPure(any)
+}
+
+// ToInt64Pure is equal to DefaultConverter.ToInt64Pure(any).
+func ToInt64Pure(any interface{}) (dst int64, err error) {
+	return DefaultConverter.ToInt64Pure(any)
+}
+
+// ToUint64Pure is equal to DefaultConverter.ToUint64Pure(any).
+func ToUint64Pure(any interface{}) (dst uint64, err error) {
+	return DefaultConverter.ToUint64Pure(any)
+}
+
+// ToFloat64Pure is equal to DefaultConverter.ToFloat64Pure(any).
+func ToFloat64Pure(any interface{}) (dst float64, err error) {
+	return DefaultConverter.ToFloat64Pure(any)
+}
+
+// ToStringPure is equal to DefaultConverter.ToStringPure(any).
+func ToStringPure(any interface{}) (dst string, err error) {
+	return DefaultConverter.ToStringPure(any)
+}
+
+// ToDurationPure is equal to DefaultConverter.ToDurationPure(any).
+func ToDurationPure(any interface{}) (dst time.Duration, err error) {
+	return DefaultConverter.ToDurationPure(any)
+}
+
+// ToTimeInLocationPure is equal to DefaultConverter.ToTimeInLocationPure(any, loc, layouts...).
+func ToTimeInLocationPure(any interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error) {
+	return DefaultConverter.ToTimeInLocationPure(any, loc, layouts...)
+}
+
+// TryParseTime is equal to DefaultConverter.TryParseTime(value, loc, layouts...).
+func TryParseTime(value string, loc *time.Location, layouts ...string) (time.Time, error) {
+	return DefaultConverter.TryParseTime(value, loc, layouts...)
+}
+
 // ToBoolPure converts any to a bool value.
 //
 // Supports the types as follow:
@@ -137,7 +135,11 @@ func ToTime(any interface{}) (dst time.Time, err error) {
 //	fmt.Stringer
 //	interface{ Bool() bool }
 //	interface{ IsZero() bool }
-func ToBoolPure(any interface{}) (dst bool, err error) {
+func (c *Converter) ToBoolPure(any interface{}) (dst bool, err error) {
+	if err = c.checkStrict(any, boolType, kindBool, isBooler); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case bool:
@@ -191,17 +193,21 @@ func ToBoolPure(any interface{}) (dst bool, err error) {
 	case fmt.Stringer:
 		dst, err = parseBool(src.String())
 	default:
-		dst, err = tryReflectToBool(reflect.ValueOf(any))
+		dst, err = c.tryReflectToBool(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToBool", any, boolType, err)
 	}
 	return
 }
 
-func tryReflectToBool(src reflect.Value) (dst bool, err error) {
+func (c *Converter) tryReflectToBool(src reflect.Value) (dst bool, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToBool(src.Elem())
+			dst, err = c.tryReflectToBool(src.Elem())
 		}
 
 	case reflect.Bool:
@@ -220,7 +226,7 @@ func tryReflectToBool(src reflect.Value) (dst bool, err error) {
 		dst = src.Uint() != 0
 
 	default:
-		err = fmt.Errorf("cast.ToBool: unsupport to convert %T to bool", src.Interface())
+		err = newError("ToBool", src.Interface(), boolType, ErrUnsupported, nil)
 	}
 
 	return
@@ -250,7 +256,11 @@ func parseBool(src string) (dst bool, err error) {
 //	[]byte
 //	error
 //	fmt.Stringer
-func ToStringPure(any interface{}) (dst string, err error) {
+func (c *Converter) ToStringPure(any interface{}) (dst string, err error) {
+	if err = c.checkStrict(any, stringType, kindString); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case bool:
@@ -294,17 +304,21 @@ func ToStringPure(any interface{}) (dst string, err error) {
 	case fmt.Stringer:
 		dst = src.String()
 	default:
-		dst, err = tryReflectToString(reflect.ValueOf(any))
+		dst, err = c.tryReflectToString(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToString", any, stringType, err)
 	}
 	return
 }
 
-func tryReflectToString(src reflect.Value) (dst string, err error) {
+func (c *Converter) tryReflectToString(src reflect.Value) (dst string, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToString(src.Elem())
+			dst, err = c.tryReflectToString(src.Elem())
 		}
 
 	case reflect.Bool:
@@ -326,7 +340,7 @@ func tryReflectToString(src reflect.Value) (dst string, err error) {
 		dst = strconv.FormatUint(src.Uint(), 10)
 
 	default:
-		err = fmt.Errorf("cast.ToString: unsupport to convert %T to string", src.Interface())
+		err = newError("ToString", src.Interface(), stringType, ErrUnsupported, nil)
 	}
 	return
 }
@@ -336,12 +350,12 @@ func tryReflectToString(src reflect.Value) (dst string, err error) {
 // Supports the types as follow:
 //
 //	~bool
-//	~string: => strconv.ParseInt
-//	~float32, ~float64
+//	~string: => strconv.ParseInt with c.IntBase, or strconv.ParseFloat and rounding for the decimal float numeral
+//	~float32, ~float64: => rounding by the rounding mode
 //	~int, ~int8, ~int16, ~int32, ~int64
 //	~uint, ~uint8, ~uint16, ~uint32, ~uint64, ~uintptr
-//	time.Duration: => N(ms)
-//	time.Time: => unix timestamp
+//	time.Duration: => N(ms), or N(c.DurationUnit) if set
+//	time.Time: => unix timestamp in c.EpochUnit, or seconds for EpochAuto
 //
 // And the pointer to types above, and the types as follow:
 //
@@ -350,7 +364,11 @@ func tryReflectToString(src reflect.Value) (dst string, err error) {
 //	fmt.Stringer
 //	interface{ Int64() int64 }
 //	interface{ Int() int64 }
-func ToInt64Pure(any interface{}) (dst int64, err error) {
+func (c *Converter) ToInt64Pure(any interface{}) (dst int64, err error) {
+	if err = c.checkStrict(any, int64Type, kindNumber, isInter); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case bool:
@@ -358,13 +376,13 @@ func ToInt64Pure(any interface{}) (dst int64, err error) {
 			dst = 1
 		}
 	case string:
-		dst, err = parseInt64(src)
+		dst, err = c.parseInt64(src)
 	case []byte:
-		dst, err = parseInt64(string(src))
+		dst, err = c.parseInt64(string(src))
 	case float32:
-		dst = int64(src)
+		dst, err = c.floatToInt64(any, float64(src), int64Type)
 	case float64:
-		dst = int64(src)
+		dst, err = c.floatToInt64(any, src, int64Type)
 	case int:
 		dst = int64(src)
 	case int8:
@@ -376,7 +394,7 @@ func ToInt64Pure(any interface{}) (dst int64, err error) {
 	case int64:
 		dst = src
 	case uint:
-		dst = int64(src)
+		dst, err = c.uintToInt64(any, uint64(src))
 	case uint8:
 		dst = int64(src)
 	case uint16:
@@ -384,35 +402,39 @@ func ToInt64Pure(any interface{}) (dst int64, err error) {
 	case uint32:
 		dst = int64(src)
 	case uint64:
-		dst = int64(src)
+		dst, err = c.uintToInt64(any, src)
 	case uintptr:
-		dst = int64(src)
+		dst, err = c.uintToInt64(any, uint64(src))
 	case time.Duration:
-		dst = int64(src / time.Millisecond)
+		dst, err = c.durationToInt(any, src)
 	case *time.Duration:
-		dst = int64(*src / time.Millisecond)
+		dst, err = c.durationToInt(any, *src)
 	case time.Time:
-		dst = src.Unix()
+		dst, err = c.timeToUnix(any, src, c.EpochUnit)
 	case *time.Time:
-		dst = src.Unix()
+		dst, err = c.timeToUnix(any, *src, c.EpochUnit)
 	case interface{ Int64() int64 }:
 		dst = src.Int64()
 	case interface{ Int() int64 }:
 		dst = src.Int()
 	case fmt.Stringer:
-		dst, err = parseInt64(src.String())
+		dst, err = c.parseInt64(src.String())
 	default:
-		dst, err = tryReflectToInt64(reflect.ValueOf(any))
+		dst, err = c.tryReflectToInt64(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToInt64", any, int64Type, err)
 	}
 	return
 }
 
-func tryReflectToInt64(src reflect.Value) (dst int64, err error) {
+func (c *Converter) tryReflectToInt64(src reflect.Value) (dst int64, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToInt64(src.Elem())
+			dst, err = c.tryReflectToInt64(src.Elem())
 		}
 
 	case reflect.Bool:
@@ -421,28 +443,41 @@ func tryReflectToInt64(src reflect.Value) (dst int64, err error) {
 		}
 
 	case reflect.String:
-		dst, err = parseInt64(src.String())
+		dst, err = c.parseInt64(src.String())
 
 	case reflect.Float32, reflect.Float64:
-		dst = int64(src.Float())
+		dst, err = c.floatToInt64(src.Interface(), src.Float(), int64Type)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		dst = src.Int()
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		dst = int64(src.Uint())
+		dst, err = c.uintToInt64(src.Interface(), src.Uint())
 
 	default:
-		err = fmt.Errorf("cast.ToInt64: unsupport to convert %T to int64", src.Interface())
+		err = newError("ToInt64", src.Interface(), int64Type, ErrUnsupported, nil)
 	}
 
 	return
 }
 
-func parseInt64(src string) (dst int64, err error) {
-	if src != "" {
-		dst, err = strconv.ParseInt(src, 0, 64)
+func (c *Converter) parseInt64(src string) (dst int64, err error) {
+	if src == "" {
+		return
 	}
+
+	dst, err = strconv.ParseInt(src, c.IntBase, 64)
+	switch {
+	case isRangeError(err):
+		wrapped := int64(wrapIntString(src, c.IntBase, 1))
+		dst, err = c.overflowInt(src, int64Type, src[0] != '-', math.MinInt64, math.MaxInt64, wrapped)
+
+	case isSyntaxError(err) && c.isDecimal() && isFloatString(src):
+		if f, _err := strconv.ParseFloat(src, 64); _err == nil {
+			dst, err = c.floatToInt64(src, f, int64Type)
+		}
+	}
+
 	return
 }
 
@@ -451,8 +486,8 @@ func parseInt64(src string) (dst int64, err error) {
 // Supports the types as follow:
 //
 //	~bool
-//	~string: => strconv.ParseUint
-//	~float32, ~float64
+//	~string: => strconv.ParseUint with c.IntBase, or strconv.ParseFloat and rounding for the decimal float numeral
+//	~float32, ~float64: => rounding by the rounding mode
 //	~int, ~int8, ~int16, ~int32, ~int64
 //	~uint, ~uint8, ~uint16, ~uint32, ~uint64, ~uintptr
 //
@@ -463,7 +498,11 @@ func parseInt64(src string) (dst int64, err error) {
 //	fmt.Stringer
 //	interface{ Uint64() uint64 }
 //	interface{ Uint() uint64 }
-func ToUint64Pure(any interface{}) (dst uint64, err error) {
+func (c *Converter) ToUint64Pure(any interface{}) (dst uint64, err error) {
+	if err = c.checkStrict(any, uint64Type, kindNumber, isUinter); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case bool:
@@ -471,44 +510,23 @@ func ToUint64Pure(any interface{}) (dst uint64, err error) {
 			dst = 1
 		}
 	case string:
-		dst, err = parseUint64(src)
+		dst, err = c.parseUint64(src)
 	case []byte:
-		dst, err = parseUint64(string(src))
+		dst, err = c.parseUint64(string(src))
 	case float32:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.floatToUint64(any, float64(src))
 	case float64:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.floatToUint64(any, src)
 	case int:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.intToUint64(any, int64(src))
 	case int8:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.intToUint64(any, int64(src))
 	case int16:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.intToUint64(any, int64(src))
 	case int32:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.intToUint64(any, int64(src))
 	case int64:
-		if src < 0 {
-			return 0, errors.New("cannot convert a negative to uint64")
-		}
-		dst = uint64(src)
+		dst, err = c.intToUint64(any, src)
 	case uint:
 		dst = uint64(src)
 	case uint8:
@@ -526,19 +544,23 @@ func ToUint64Pure(any interface{}) (dst uint64, err error) {
 	case interface{ Uint() uint64 }:
 		dst = src.Uint()
 	case fmt.Stringer:
-		dst, err = parseUint64(src.String())
+		dst, err = c.parseUint64(src.String())
 	default:
-		dst, err = tryReflectToUint64(reflect.ValueOf(any))
+		dst, err = c.tryReflectToUint64(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToUint64", any, uint64Type, err)
 	}
 	return
 }
 
-func tryReflectToUint64(src reflect.Value) (dst uint64, err error) {
+func (c *Converter) tryReflectToUint64(src reflect.Value) (dst uint64, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToUint64(src.Elem())
+			dst, err = c.tryReflectToUint64(src.Elem())
 		}
 
 	case reflect.Bool:
@@ -547,39 +569,57 @@ func tryReflectToUint64(src reflect.Value) (dst uint64, err error) {
 		}
 
 	case reflect.String:
-		dst, err = parseUint64(src.String())
+		dst, err = c.parseUint64(src.String())
 
 	case reflect.Float32, reflect.Float64:
-		if v := src.Float(); v < 0 {
-			err = errors.New("cannot convert a negative float to uint64")
-		} else {
-			dst = uint64(v)
-		}
+		dst, err = c.floatToUint64(src.Interface(), src.Float())
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if v := src.Int(); v < 0 {
-			err = errors.New("cannot convert a negative integer to uint64")
-		} else {
-			dst = uint64(v)
-		}
+		dst, err = c.intToUint64(src.Interface(), src.Int())
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		dst = src.Uint()
 
 	default:
-		err = fmt.Errorf("cast.ToUint64: unsupport to convert %T to uint64", src.Interface())
+		err = newError("ToUint64", src.Interface(), uint64Type, ErrUnsupported, nil)
 	}
 
 	return
 }
 
-func parseUint64(src string) (dst uint64, err error) {
-	if src != "" {
-		dst, err = strconv.ParseUint(src, 0, 64)
+func (c *Converter) parseUint64(src string) (dst uint64, err error) {
+	if src == "" {
+		return
+	}
+
+	dst, err = strconv.ParseUint(src, c.IntBase, 64)
+	switch {
+	case isRangeError(err): // ParseUint rejects the negative.
+		dst, err = c.overflowUint(src, uint64Type, true, math.MaxUint64, wrapIntString(src, c.IntBase, 1))
+
+	case isSyntaxError(err) && c.isDecimal() && isFloatString(src):
+		if f, _err := strconv.ParseFloat(src, 64); _err == nil {
+			dst, err = c.floatToUint64(src, f)
+		}
 	}
+
 	return
 }
 
+// isDecimal reports whether a float string may be parsed as an integer,
+// that's, the integer base is 0 or 10.
+func (c *Converter) isDecimal() bool {
+	return c.IntBase == 0 || c.IntBase == 10
+}
+
+func isSyntaxError(err error) bool {
+	return err != nil && errors.Is(err, strconv.ErrSyntax)
+}
+
+func isRangeError(err error) bool {
+	return err != nil && errors.Is(err, strconv.ErrRange)
+}
+
 // ToFloat64Pure converts any to a float64 value.
 //
 // Supports the types as follow:
@@ -589,7 +629,7 @@ func parseUint64(src string) (dst uint64, err error) {
 //	~float32, ~float64
 //	~int, ~int8, ~int16, ~int32, ~int64
 //	~uint, ~uint8, ~uint16, ~uint32, ~uint64, ~uintptr
-//	time.Duration: => F<s>
+//	time.Duration: => F<s>, or F<c.DurationUnit> if set
 //
 // And the pointer to types above, and the types as follow:
 //
@@ -598,7 +638,11 @@ func parseUint64(src string) (dst uint64, err error) {
 //	fmt.Stringer
 //	interface{ Float64() float64 }
 //	interface{ Float() float64 }
-func ToFloat64Pure(any interface{}) (dst float64, err error) {
+func (c *Converter) ToFloat64Pure(any interface{}) (dst float64, err error) {
+	if err = c.checkStrict(any, float64Type, kindNumber, isFloater); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case bool:
@@ -614,7 +658,7 @@ func ToFloat64Pure(any interface{}) (dst float64, err error) {
 	case float64:
 		dst = src
 	case int:
-		dst = float64(src)
+		dst, err = c.intToFloat64(any, int64(src))
 	case int8:
 		dst = float64(src)
 	case int16:
@@ -622,9 +666,9 @@ func ToFloat64Pure(any interface{}) (dst float64, err error) {
 	case int32:
 		dst = float64(src)
 	case int64:
-		dst = float64(src)
+		dst, err = c.intToFloat64(any, src)
 	case uint:
-		dst = float64(src)
+		dst, err = c.uintToFloat64(any, uint64(src))
 	case uint8:
 		dst = float64(src)
 	case uint16:
@@ -632,13 +676,13 @@ func ToFloat64Pure(any interface{}) (dst float64, err error) {
 	case uint32:
 		dst = float64(src)
 	case uint64:
-		dst = float64(src)
+		dst, err = c.uintToFloat64(any, src)
 	case uintptr:
-		dst = float64(src)
+		dst, err = c.uintToFloat64(any, uint64(src))
 	case time.Duration:
-		dst = float64(src) / float64(time.Second)
+		dst = c.durationToFloat(src)
 	case *time.Duration:
-		dst = float64(*src) / float64(time.Second)
+		dst = c.durationToFloat(*src)
 	case interface{ Float64() float64 }:
 		dst = src.Float64()
 	case interface{ Float() float64 }:
@@ -646,17 +690,21 @@ func ToFloat64Pure(any interface{}) (dst float64, err error) {
 	case fmt.Stringer:
 		dst, err = parseFloat64(src.String())
 	default:
-		dst, err = tryReflectToFloat64(reflect.ValueOf(any))
+		dst, err = c.tryReflectToFloat64(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToFloat64", any, float64Type, err)
 	}
 	return
 }
 
-func tryReflectToFloat64(src reflect.Value) (dst float64, err error) {
+func (c *Converter) tryReflectToFloat64(src reflect.Value) (dst float64, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToFloat64(src.Elem())
+			dst, err = c.tryReflectToFloat64(src.Elem())
 		}
 
 	case reflect.Bool:
@@ -671,13 +719,13 @@ func tryReflectToFloat64(src reflect.Value) (dst float64, err error) {
 		dst = src.Float()
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		dst = float64(src.Int())
+		dst, err = c.intToFloat64(src.Interface(), src.Int())
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		dst = float64(src.Uint())
+		dst, err = c.uintToFloat64(src.Interface(), src.Uint())
 
 	default:
-		err = fmt.Errorf("cast.ToFloat64: unsupport to convert %T to float64", src.Interface())
+		err = newError("ToFloat64", src.Interface(), float64Type, ErrUnsupported, nil)
 	}
 
 	return
@@ -694,8 +742,8 @@ func parseFloat64(src string) (dst float64, err error) {
 //
 // Supports the types as follow:
 //
-//	~string: => N<ms> if integer string, else time.ParseDuration
-//	~float32, ~float64: => F<s>
+//	~string: => N<ms> if integer or float string, else time.ParseDuration
+//	~float32, ~float64: => F<s>, and rounding to nanoseconds by the rounding mode
 //	~int, ~int8, ~int16, ~int32, ~int64: => N<ms>
 //	~uint, ~uint8, ~uint16, ~uint32, ~uint64, ~uintptr: => N<ms>
 //	time.Duration
@@ -706,39 +754,49 @@ func parseFloat64(src string) (dst float64, err error) {
 //	[]byte
 //	fmt.Stringer
 //	interface{ Duration() time.Duration }
-func ToDurationPure(any interface{}) (dst time.Duration, err error) {
+//
+// If c.DurationUnit is set, the unit of all the numbers above is it
+// instead of ms and s.
+//
+// Notice: like float64, the named float types, such as "type F float64",
+// are in seconds, which were in milliseconds in the old versions.
+func (c *Converter) ToDurationPure(any interface{}) (dst time.Duration, err error) {
+	if err = c.checkStrict(any, durationType, kindDuration, isDurationer); err != nil {
+		return
+	}
+
 	switch src := any.(type) {
 	case nil:
 	case string:
-		dst, err = parseDuration(src)
+		dst, err = c.parseDuration(src)
 	case []byte:
-		dst, err = parseDuration(string(src))
+		dst, err = c.parseDuration(string(src))
 	case float32:
-		dst = time.Duration(float64(src) * float64(time.Second))
+		dst, err = c.floatToDuration(any, float64(src))
 	case float64:
-		dst = time.Duration(src * float64(time.Second))
+		dst, err = c.floatToDuration(any, src)
 	case int:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.intToDuration(any, int64(src))
 	case int8:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.intToDuration(any, int64(src))
 	case int16:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.intToDuration(any, int64(src))
 	case int32:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.intToDuration(any, int64(src))
 	case int64:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.intToDuration(any, src)
 	case uint:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, uint64(src))
 	case uint8:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, uint64(src))
 	case uint16:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, uint64(src))
 	case uint32:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, uint64(src))
 	case uint64:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, src)
 	case uintptr:
-		dst = time.Duration(src) * time.Millisecond
+		dst, err = c.uintToDuration(any, uint64(src))
 	case time.Duration:
 		dst = src
 	case *time.Duration:
@@ -746,41 +804,45 @@ func ToDurationPure(any interface{}) (dst time.Duration, err error) {
 	case interface{ Duration() time.Duration }:
 		dst = src.Duration()
 	case fmt.Stringer:
-		dst, err = parseDuration(src.String())
+		dst, err = c.parseDuration(src.String())
 	default:
-		dst, err = tryReflectToDuration(reflect.ValueOf(any))
+		dst, err = c.tryReflectToDuration(reflect.ValueOf(any))
+	}
+
+	if err != nil {
+		err = wrapError("ToDuration", any, durationType, err)
 	}
 	return
 }
 
-func tryReflectToDuration(src reflect.Value) (dst time.Duration, err error) {
+func (c *Converter) tryReflectToDuration(src reflect.Value) (dst time.Duration, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToDuration(src.Elem())
+			dst, err = c.tryReflectToDuration(src.Elem())
 		}
 
 	case reflect.String:
-		dst, err = parseDuration(src.String())
+		dst, err = c.parseDuration(src.String())
 
-	case reflect.Float32, reflect.Float64:
-		dst = time.Duration(src.Float()) * time.Millisecond
+	case reflect.Float32, reflect.Float64: // In seconds like float64, not milliseconds.
+		dst, err = c.floatToDuration(src.Interface(), src.Float())
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		dst = time.Duration(src.Int()) * time.Millisecond
+		dst, err = c.intToDuration(src.Interface(), src.Int())
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		dst = time.Duration(src.Uint()) * time.Millisecond
+		dst, err = c.uintToDuration(src.Interface(), src.Uint())
 
 	default:
-		err = fmt.Errorf("cast.ToDuration: unsupport to convert %T to time.Duration", src.Interface())
+		err = newError("ToDuration", src.Interface(), durationType, ErrUnsupported, nil)
 	}
 
 	return
 }
 
-func parseDuration(src string) (dst time.Duration, err error) {
+func (c *Converter) parseDuration(src string) (dst time.Duration, err error) {
 	_len := len(src)
 	if _len == 0 {
 		return
@@ -789,8 +851,19 @@ func parseDuration(src string) (dst time.Duration, err error) {
 	switch src[_len-1] {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		var i int64
-		i, err = strconv.ParseInt(src, 10, 64)
-		dst = time.Duration(i) * time.Millisecond
+		if i, err = strconv.ParseInt(src, 10, 64); err == nil {
+			dst, err = c.intToDuration(src, i)
+		} else if isRangeError(err) {
+			wrapped := int64(wrapIntString(src, 10, int64(c.intDurationUnit())))
+			i, err = c.overflowInt(src, durationType, src[0] != '-', math.MinInt64, math.MaxInt64, wrapped)
+			dst = time.Duration(i)
+		} else if isSyntaxError(err) && isFloatString(src) {
+			if f, _err := strconv.ParseFloat(src, 64); _err == nil {
+				var ns int64
+				ns, err = c.floatToInt64(src, f*float64(c.intDurationUnit()), durationType)
+				dst = time.Duration(ns)
+			}
+		}
 	default:
 		dst, err = time.ParseDuration(src)
 	}
@@ -803,9 +876,9 @@ func parseDuration(src string) (dst time.Duration, err error) {
 // Supports the types as follow:
 //
 //	~string: => TryParseTime
-//	~float32, ~float64: => unix timestamp
-//	~int, ~int8, ~int16, ~int32, ~int64: => unix timestamp
-//	~uint, ~uint8, ~uint16, ~uint32, ~uint64: => unix timestamp
+//	~float32, ~float64: => unix timestamp in c.EpochUnit, keeping the fractional part
+//	~int, ~int8, ~int16, ~int32, ~int64: => unix timestamp in c.EpochUnit
+//	~uint, ~uint8, ~uint16, ~uint32, ~uint64: => unix timestamp in c.EpochUnit
 //	time.Time
 //
 // And the pointer to types above, and the types as follow:
@@ -815,36 +888,37 @@ func parseDuration(src string) (dst time.Duration, err error) {
 //	fmt.Stringer
 //	interface{ Time() time.Time }
 //
-// If loc is nil, use defaults.TimeLocation instead.
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
 // If any is a string-like, use TryParseTime to parse it with layouts.
-func ToTimeInLocationPure(any interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error) {
-	if loc == nil {
-		loc = defaults.TimeLocation.Get()
+func (c *Converter) ToTimeInLocationPure(any interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error) {
+	loc = c.location(loc)
+	if err = c.checkStrict(any, timeType, kindTime, isTimer); err != nil {
+		return
 	}
 
 	switch src := any.(type) {
 	case nil:
 		dst = dst.In(loc)
 	case string:
-		dst, err = TryParseTime(src, loc, layouts...)
+		dst, err = c.TryParseTime(src, loc, layouts...)
 	case []byte:
-		dst, err = TryParseTime(string(src), loc, layouts...)
+		dst, err = c.TryParseTime(string(src), loc, layouts...)
 	case float32:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst, err = c.floatToTime(any, float64(src), 32, loc)
 	case float64:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst, err = c.floatToTime(any, src, 64, loc)
 	case int:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst = c.intToTime(int64(src), loc)
 	case int32:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst = c.intToTime(int64(src), loc)
 	case int64:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst = c.intToTime(src, loc)
 	case uint:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst, err = c.uintToTime(any, uint64(src), loc)
 	case uint32:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst, err = c.uintToTime(any, uint64(src), loc)
 	case uint64:
-		dst = time.Unix(int64(src), 0).In(loc)
+		dst, err = c.uintToTime(any, src, loc)
 	case time.Time:
 		dst = src.In(loc)
 	case *time.Time:
@@ -852,37 +926,40 @@ func ToTimeInLocationPure(any interface{}, loc *time.Location, layouts ...string
 	case interface{ Time() time.Time }:
 		dst = src.Time()
 	case fmt.Stringer:
-		dst, err = TryParseTime(src.String(), loc, layouts...)
+		dst, err = c.TryParseTime(src.String(), loc, layouts...)
 	default:
-		dst, err = tryReflectToTimeInLocation(reflect.ValueOf(any), loc, layouts...)
+		dst, err = c.tryReflectToTimeInLocation(reflect.ValueOf(any), loc, layouts...)
 	}
 
+	if err != nil {
+		err = wrapError("ToTimeInLocation", any, timeType, err)
+	}
 	return
 }
 
-func tryReflectToTimeInLocation(src reflect.Value, loc *time.Location,
+func (c *Converter) tryReflectToTimeInLocation(src reflect.Value, loc *time.Location,
 	layouts ...string) (dst time.Time, err error) {
 	switch src.Kind() {
 	case reflect.Invalid:
 	case reflect.Pointer:
 		if !src.IsNil() {
-			dst, err = tryReflectToTimeInLocation(src.Elem(), loc, layouts...)
+			dst, err = c.tryReflectToTimeInLocation(src.Elem(), loc, layouts...)
 		}
 
 	case reflect.String:
-		dst, err = TryParseTime(src.String(), loc, layouts...)
+		dst, err = c.TryParseTime(src.String(), loc, layouts...)
 
 	case reflect.Float32, reflect.Float64:
-		dst = time.Unix(int64(src.Float()), 0).In(loc)
+		dst, err = c.floatToTime(src.Interface(), src.Float(), src.Type().Bits(), loc)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		dst = time.Unix(src.Int(), 0).In(loc)
+		dst = c.intToTime(src.Int(), loc)
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		dst = time.Unix(int64(src.Uint()), 0).In(loc)
+		dst, err = c.uintToTime(src.Interface(), src.Uint(), loc)
 
 	default:
-		err = fmt.Errorf("cast.ToTimeInLocation: unsupport to convert %T to time.Time", src.Interface())
+		err = newError("ToTimeInLocation", src.Interface(), timeType, ErrUnsupported, nil)
 	}
 
 	return
@@ -912,36 +989,60 @@ func isIntegerString(s string) bool {
 
 // TryParseTime tries to parse the string value with the layouts in turn to time.Time.
 //
-// If loc is nil, use defaults.TimeLocation instead.
-// If layouts is empty, use defaults.TimeFormats instead.
-// If value is a integer string, it will be parsee as the unix timestamp.
-func TryParseTime(value string, loc *time.Location, layouts ...string) (time.Time, error) {
-	if loc == nil {
-		loc = defaults.TimeLocation.Get()
-	}
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+// If layouts is empty, use c.Layouts or defaults.TimeFormats instead.
+// If value is a integer or decimal string, such as "1700000000.123",
+// it will be parsed as the unix timestamp in c.EpochUnit,
+// and the out-of-range integer part is handled by c.Overflow.
+// If c.RelativeTime is true, value may be the relative time expression,
+// such as "now-15m", which is parsed by ParseRelativeTime.
+//
+// The layouts are tried by TimeParser, which is cached by the layouts,
+// and the ones not used recently are evicted if there are too many.
+func (c *Converter) TryParseTime(value string, loc *time.Location, layouts ...string) (time.Time, error) {
+	t, _, err := c.TryParseTimeLayout(value, loc, layouts...)
+	return t, err
+}
+
+// TryParseTimeLayout is the same as TryParseTime, but also returns
+// the matched layout, which is empty if value is not parsed by a layout,
+// such as the unix timestamp.
+func (c *Converter) TryParseTimeLayout(value string, loc *time.Location, layouts ...string) (time.Time, string, error) {
+	loc = c.location(loc)
 
 	switch value {
 	case "", "0000-00-00 00:00:00", "0000-00-00 00:00:00.000", "0000-00-00 00:00:00.000000":
-		return time.Time{}.In(loc), nil
+		return time.Time{}.In(loc), "", nil
 	}
 
 	if isIntegerString(value) {
 		i, err := strconv.ParseInt(value, 10, 64)
-		return time.Unix(i, 0).In(loc), err
+		if isRangeError(err) {
+			i, err = c.epochOverflow(value, value)
+		}
+		if err != nil {
+			return time.Time{}.In(loc), "", wrapError("TryParseTime", value, timeType, err)
+		}
+		return c.intToTime(i, loc), "", nil
 	}
 
-	if len(layouts) == 0 {
-		layouts = defaults.TimeFormats.Get()
-		if len(layouts) == 0 {
-			panic("TryParseTime: no time format layouts")
-		}
+	if isDecimalString(value) {
+		t, err := c.decimalToTime(value, value, loc)
+		return t, "", err
 	}
 
-	for _, layout := range layouts {
-		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
-			return t, nil
+	if c.RelativeTime {
+		if t, ok := c.parseRelativeTime(value, loc); ok {
+			return t, "", nil
 		}
 	}
 
-	return time.Time{}.In(loc), fmt.Errorf("unable to parse time '%s'", value)
+	if layouts = c.layouts(layouts); len(layouts) == 0 {
+		panic("TryParseTime: no time format layouts")
+	}
+
+	if p := timeParsers.Get(layouts); p != nil {
+		return p.Parse(value, loc)
+	}
+	return parseTimeLayouts(value, loc, layouts)
 }
@@ -33,3 +33,8 @@ func MustToTimeInLocation(any interface{}, loc *time.Location, layouts ...string
 func MustParseTime(value string, loc *time.Location, layouts ...string) time.Time {
 	return Must(TryParseTime(value, loc, layouts...))
 }
+
+// MustTo is the same as To, but panics if there is an error.
+func MustTo[T any](src interface{}) T {
+	return Must(To[T](src))
+}
@@ -34,3 +34,9 @@ func ExampleMustToTimeInLocation() {
 	MustToTimeInLocation(1234567890, nil)
 	// Output:
 }
+
+func ExampleMustTo() {
+	MustTo[int32]("123")
+	MustTo[time.Duration]("1s")
+	// Output:
+}
@@ -247,3 +247,28 @@ func TestIsIntegerString(t *testing.T) {
 		t.Error("2006-01-02: expect false, but got true")
 	}
 }
+
+func TestToDurationNamedFloat(t *testing.T) {
+	type seconds float64
+	type seconds32 float32
+
+	if v, err := ToDuration(seconds(1.5)); err != nil {
+		t.Error(err)
+	} else if v != 1500*time.Millisecond {
+		t.Errorf("expect %s, but got %s", 1500*time.Millisecond, v)
+	}
+
+	if v, err := ToDuration(seconds32(2)); err != nil {
+		t.Error(err)
+	} else if v != 2*time.Second {
+		t.Errorf("expect %s, but got %s", 2*time.Second, v)
+	}
+
+	c := NewConverter(WithRounding(RoundHalfEven))
+	c.DurationUnit = time.Nanosecond
+	if v, err := c.ToDuration(seconds(2.5)); err != nil {
+		t.Error(err)
+	} else if v != 2 {
+		t.Errorf("expect %d, but got %d", 2, v)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/xgfone/go-defaults"
+)
+
+// DefaultConverter is the default converter used by the package-level functions,
+// such as ToBool, ToInt64, ToTimeInLocation, Set, etc.
+var DefaultConverter = NewConverter()
+
+// Converter is used to convert a value between the different types,
+// which owns its hooks, default time location, layouts and policies.
+//
+// The zero value is ready to use. But a converter should not be modified
+// after it is used by more than one goroutine.
+type Converter struct {
+	// Define some hook functions to intercept the ToXXX conversion.
+	//
+	// ToTimeHook receives the arguments of ToTimeInLocation as they are,
+	// so loc may be nil and layouts may be empty.
+	ToBoolHook     func(src interface{}) (dst bool, err error)
+	ToInt64Hook    func(src interface{}) (dst int64, err error)
+	ToUint64Hook   func(src interface{}) (dst uint64, err error)
+	ToFloat64Hook  func(src interface{}) (dst float64, err error)
+	ToStringHook   func(src interface{}) (dst string, err error)
+	ToDurationHook func(src interface{}) (dst time.Duration, err error)
+	ToTimeHook     func(src interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error)
+
+	// Location is the default location used to convert a value to time.Time.
+	//
+	// If nil, use defaults.TimeLocation instead.
+	Location *time.Location
+
+	// Layouts is the default layouts used to parse a string to time.Time.
+	//
+	// If empty, use defaults.TimeFormats instead.
+	Layouts []string
+
+	// RelativeTime indicates whether to parse the relative time expression,
+	// such as "now-15m", by TryParseTime before the layouts.
+	// See ParseRelativeTime.
+	//
+	// Default: false
+	RelativeTime bool
+
+	// Now returns the current time, which is the anchor of the relative
+	// time expression, so it can be fixed to get the deterministic result.
+	//
+	// If nil, use time.Now instead.
+	Now func() time.Time
+
+	// DurationUnit is the unit of the number converted to or from
+	// time.Duration, such as time.Second.
+	//
+	// If 0, use time.Millisecond for the integer and time.Second for the float.
+	DurationUnit time.Duration
+
+	// EpochUnit is the unit of the unix timestamp converted to or from
+	// time.Time, such as EpochMilli.
+	//
+	// Default: EpochSecond
+	EpochUnit EpochUnit
+
+	// IntBase is the base to parse a string to an integer,
+	// which is the same as the argument base of strconv.ParseInt.
+	//
+	// If 0, the base is implied by the string prefix, such as "0x".
+	IntBase int
+
+	// Separator is used to split a string into a slice, such as ToSlice.
+	//
+	// If empty, use "," instead.
+	Separator string
+
+	// Overflow is the policy to handle the value overflowing the target type.
+	//
+	// Default: OverflowReject
+	Overflow OverflowPolicy
+
+	// Rounding is the mode to round a float to an integer, which is used
+	// to convert a float or float string to an integer, or the nanoseconds
+	// of time.Time or time.Duration.
+	//
+	// Default: RoundTruncate
+	Rounding RoundingMode
+
+	// Lossless indicates whether to reject the conversions losing
+	// the information with ErrPrecisionLoss, such as
+	//
+	//   - a float with the fractional part to an integer
+	//   - a unix timestamp with the part below the nanosecond to time.Time
+	//   - an integer beyond ±2^53 to float64
+	//   - a float64 to float32 with the precision loss
+	//   - a time.Time with the part below the epoch unit to the unix timestamp
+	//   - a time.Duration with the sub-millisecond part to the milliseconds
+	//
+	// Default: false
+	Lossless bool
+
+	// Strict indicates whether to only allow the conversions between
+	// the values of the same kind, such as numeric-to-numeric with the
+	// range checks, string-to-string and time-to-time, and reject
+	// the cross-kind coercions like bool<->number or number<->string.
+	//
+	// nil is always allowed and converted to the zero value. And the value
+	// implementing the accessor interface for the target type, such as
+	// interface{ Int64() int64 } for int64, is also allowed.
+	//
+	// Default: false
+	Strict bool
+
+	// KeyMatching is the mode to match the source keys with the keys
+	// of the struct fields when decoding a struct.
+	//
+	// Default: KeyMatchExact
+	KeyMatching KeyMatching
+
+	// FieldKeys returns the additional candidate keys of the struct field
+	// when decoding a struct, besides the tag name or field name.
+	//
+	// Default: nil
+	FieldKeys func(field reflect.StructField) []string
+
+	// ErrorUnused indicates whether to return an error with ErrUnusedKeys
+	// when decoding a struct if some keys of the source are not used.
+	//
+	// Default: false
+	ErrorUnused bool
+
+	// hooks is the hooks keyed by the source and destination types.
+	hooks map[hookKey]Hook
+
+	// ifaces is the interface source types of the hooks keyed by
+	// the destination type, in the order of registration.
+	ifaces map[reflect.Type][]reflect.Type
+
+	// legacy indicates whether the converter is derived from DefaultConverter
+	// by Clone or With, which also uses the deprecated package-level hooks.
+	legacy bool
+
+	// state is the state of the current decoding, which is nil
+	// if not tracking the decode metadata.
+	state *decodeState
+}
+
+// Option is used to configure the converter.
+type Option func(*Converter)
+
+// NewConverter returns a new converter with the options.
+func NewConverter(options ...Option) *Converter {
+	c := new(Converter)
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// With returns a copy of the converter configured by the options,
+// which is convenient to customize the conversion for a single call,
+// for example,
+//
+//	DefaultConverter.With(WithOverflow(OverflowSaturate)).ToInt8(src)
+func (c *Converter) With(options ...Option) *Converter {
+	nc := c.Clone()
+	for _, option := range options {
+		option(nc)
+	}
+	return nc
+}
+
+// Clone returns a copy of the converter, which may be modified
+// without affecting the original one.
+//
+// The copy of DefaultConverter also uses the deprecated package-level hooks,
+// such as ToInt64Hook, if its own hook is nil.
+func (c *Converter) Clone() *Converter {
+	nc := *c
+	nc.legacy = c.useLegacyHooks()
+	if len(c.Layouts) > 0 {
+		nc.Layouts = append([]string(nil), c.Layouts...)
+	}
+	if len(c.hooks) > 0 {
+		nc.hooks = make(map[hookKey]Hook, len(c.hooks))
+		for key, hook := range c.hooks {
+			nc.hooks[key] = hook
+		}
+	}
+	if len(c.ifaces) > 0 {
+		nc.ifaces = make(map[reflect.Type][]reflect.Type, len(c.ifaces))
+		for dtype, types := range c.ifaces {
+			nc.ifaces[dtype] = append([]reflect.Type(nil), types...)
+		}
+	}
+	return &nc
+}
+
+func (c *Converter) location(loc *time.Location) *time.Location {
+	switch {
+	case loc != nil:
+		return loc
+	case c.Location != nil:
+		return c.Location
+	default:
+		return defaults.TimeLocation.Get()
+	}
+}
+
+func (c *Converter) layouts(layouts []string) []string {
+	switch {
+	case len(layouts) > 0:
+		return layouts
+	case len(c.Layouts) > 0:
+		return c.Layouts
+	default:
+		return defaults.TimeFormats.Get()
+	}
+}
+
+// useLegacyHooks reports whether the converter uses the deprecated
+// package-level hooks if its own hook is nil.
+func (c *Converter) useLegacyHooks() bool {
+	return c == DefaultConverter || c.legacy
+}
+
+// ToBool prefers to use ToBoolHook to convert any to a bool value
+// rather than ToBoolPure.
+func (c *Converter) ToBool(any interface{}) (dst bool, err error) {
+	hook := c.ToBoolHook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToBoolHook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToBoolPure(any)
+	}
+	return
+}
+
+// ToInt64 prefers to use ToInt64Hook to convert any to a int64 value
+// rather than ToInt64Pure.
+func (c *Converter) ToInt64(any interface{}) (dst int64, err error) {
+	hook := c.ToInt64Hook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToInt64Hook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToInt64Pure(any)
+	}
+	return
+}
+
+// ToUint64 prefers to use ToUint64Hook to convert any to a uint64 value
+// rather than ToUint64Pure.
+func (c *Converter) ToUint64(any interface{}) (dst uint64, err error) {
+	hook := c.ToUint64Hook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToUint64Hook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToUint64Pure(any)
+	}
+	return
+}
+
+// ToFloat64 prefers to use ToFloat64Hook to convert any to a float64 value
+// rather than ToFloat64Pure.
+func (c *Converter) ToFloat64(any interface{}) (dst float64, err error) {
+	hook := c.ToFloat64Hook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToFloat64Hook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToFloat64Pure(any)
+	}
+	return
+}
+
+// ToString prefers to use ToStringHook to convert any to a string value
+// rather than ToStringPure.
+func (c *Converter) ToString(any interface{}) (dst string, err error) {
+	hook := c.ToStringHook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToStringHook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToStringPure(any)
+	}
+	return
+}
+
+// ToDuration prefers to use ToDurationHook to convert any to a time.Duration value
+// rather than ToDurationPure.
+func (c *Converter) ToDuration(any interface{}) (dst time.Duration, err error) {
+	hook := c.ToDurationHook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToDurationHook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any)
+	} else {
+		dst, err = c.ToDurationPure(any)
+	}
+	return
+}
+
+// ToTimeInLocation prefers to use ToTimeHook to convert any to a time.Time value
+// rather than ToTimeInLocationPure.
+func (c *Converter) ToTimeInLocation(any interface{}, loc *time.Location, layouts ...string) (dst time.Time, err error) {
+	hook := c.ToTimeHook
+	if hook == nil && c.useLegacyHooks() {
+		hook = ToTimeHook // Compatible with the deprecated package-level hook.
+	}
+
+	if hook != nil {
+		dst, err = hook(any, loc, layouts...)
+	} else {
+		dst, err = c.ToTimeInLocationPure(any, loc, layouts...)
+	}
+	return
+}
+
+// ToTime is a convenient method, which is equal to c.ToTimeInLocation(any, nil).
+func (c *Converter) ToTime(any interface{}) (dst time.Time, err error) {
+	return c.ToTimeInLocation(any, nil)
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleConverter() {
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	c := NewConverter()
+	c.Location = loc
+	c.Layouts = []string{"2006/01/02 15:04:05"}
+	c.ToBoolHook = func(src interface{}) (bool, error) {
+		if s, ok := src.(string); ok && s == "yes" {
+			return true, nil
+		}
+		return ToBoolPure(src)
+	}
+
+	fmt.Println(c.ToBool("yes"))
+	fmt.Println(c.ToTime("2009/02/14 07:31:30"))
+
+	var v int
+	fmt.Println(c.Set(&v, "123"), v)
+
+	// The default converter is not affected.
+	_, err := ToBool("yes")
+	fmt.Println(err != nil)
+
+	// Output:
+	// true <nil>
+	// 2009-02-14 07:31:30 +0800 UTC+8 <nil>
+	// <nil> 123
+	// true
+}
+
+func TestConverterClone(t *testing.T) {
+	c1 := NewConverter()
+	c1.Layouts = []string{time.RFC3339}
+	c1.ToInt64Hook = func(src interface{}) (int64, error) { return 1, nil }
+
+	c2 := c1.Clone()
+	c2.Layouts[0] = time.DateTime
+	c2.ToInt64Hook = nil
+
+	if c1.Layouts[0] != time.RFC3339 {
+		t.Errorf("expect layout '%s', but got '%s'", time.RFC3339, c1.Layouts[0])
+	}
+
+	if v, err := c1.ToInt64("123"); err != nil {
+		t.Error(err)
+	} else if v != 1 {
+		t.Errorf("expect %d, but got %d", 1, v)
+	}
+
+	if v, err := c2.ToInt64("123"); err != nil {
+		t.Error(err)
+	} else if v != 123 {
+		t.Errorf("expect %d, but got %d", 123, v)
+	}
+}
+
+func TestConverterTimeHook(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	var gotloc *time.Location
+	var gotlayouts []string
+
+	c := NewConverter()
+	c.Location = loc
+	c.Layouts = []string{time.DateOnly}
+	c.ToTimeHook = func(src interface{}, loc *time.Location, layouts ...string) (time.Time, error) {
+		gotloc, gotlayouts = loc, layouts
+		return c.ToTimeInLocationPure(src, loc, layouts...)
+	}
+
+	if v, err := c.ToTime("2009-02-14"); err != nil {
+		t.Error(err)
+	} else if expect := time.Date(2009, 2, 14, 0, 0, 0, 0, loc); !v.Equal(expect) {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+
+	// The hook receives the original arguments, not c.Location and c.Layouts.
+	if gotloc != nil {
+		t.Errorf("expect the nil location, but got %s", gotloc)
+	}
+	if len(gotlayouts) != 0 {
+		t.Errorf("unexpected layouts %v", gotlayouts)
+	}
+}
+
+func TestDeprecatedHook(t *testing.T) {
+	ToInt64Hook = func(src interface{}) (int64, error) { return 123, nil }
+	defer func() { ToInt64Hook = nil }()
+
+	if v, err := ToInt64("1"); err != nil {
+		t.Error(err)
+	} else if v != 123 {
+		t.Errorf("expect %d, but got %d", 123, v)
+	}
+
+	var v int64
+	if err := Set(&v, "1"); err != nil {
+		t.Error(err)
+	} else if v != 123 {
+		t.Errorf("expect %d, but got %d", 123, v)
+	}
+
+	if v, err := NewConverter().ToInt64("1"); err != nil {
+		t.Error(err)
+	} else if v != 1 {
+		t.Errorf("expect %d, but got %d", 1, v)
+	}
+
+	c := DefaultConverter.With(WithOverflow(OverflowSaturate))
+	if v, err := c.ToInt64("1"); err != nil {
+		t.Error(err)
+	} else if v != 123 {
+		t.Errorf("expect %d, but got %d", 123, v)
+	}
+
+	c = c.Clone()
+	c.ToInt64Hook = func(src interface{}) (int64, error) { return 456, nil }
+	if v, err := c.ToInt64("1"); err != nil {
+		t.Error(err)
+	} else if v != 456 {
+		t.Errorf("expect %d, but got %d", 456, v)
+	}
+
+	// The hook receives the original arguments.
+	var hookLoc *time.Location
+	var hookLayouts []string
+	ToTimeHook = func(src interface{}, loc *time.Location, layouts ...string) (time.Time, error) {
+		hookLoc, hookLayouts = loc, layouts
+		return time.Time{}, nil
+	}
+	defer func() { ToTimeHook = nil }()
+
+	if _, err := ToTime("2023-01-01"); err != nil {
+		t.Error(err)
+	} else if hookLoc != nil || hookLayouts != nil {
+		t.Errorf("expect the nil location and layouts, but got %v and %v", hookLoc, hookLayouts)
+	}
+}
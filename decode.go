@@ -0,0 +1,331 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Decode is equal to DefaultConverter.Decode(dst, src).
+func Decode(dst, src interface{}) error {
+	return DefaultConverter.Decode(dst, src)
+}
+
+// Decode decodes src, such as map[string]interface{}, into dst,
+// which must be a non-nil pointer, typically to a struct.
+//
+// For the struct, each exported field is set to the value in src
+// whose key matches the field name, and is left unchanged if missing
+// and having no default value. The keys are matched by c.KeyMatching,
+// and c.FieldKeys may provide the additional candidate keys.
+// The key and conversion of the field may be customized by the tag,
+// see TagName, and the default value may be declared by the tag,
+// see DefaultTagName.
+// The nested structs, pointers, slices and maps are decoded recursively,
+// and the leaf values are converted by c.Set.
+//
+// For the struct, src may be a map with any key type, a struct or
+// a pointer to them, which is converted by ToStringMap, and others,
+// such as a string, return a *ConversionError with ErrUnsupported.
+// nil is regarded as the empty map.
+//
+// If failing to decode a field, the returned *ConversionError
+// reports it by the field Path, such as "Servers[0].Port".
+//
+// If c.ErrorUnused is true and some keys of src are not used, return
+// a *ConversionError with ErrUnusedKeys, which lists all of them.
+func (c *Converter) Decode(dst, src interface{}) error {
+	return c.DecodeWithMetadata(dst, src, nil)
+}
+
+// DecodeMetadata is the metadata of decoding a struct, whose elements
+// are the paths of the keys, such as "Servers[0].Port".
+type DecodeMetadata struct {
+	// Keys is the keys of the source decoded into the struct fields.
+	Keys []string
+
+	// Unused is the keys of the source not decoded into any struct field.
+	Unused []string
+
+	// Unset is the keys of the struct fields missing from the source
+	// or omitted by omitempty, which may be set to the default values.
+	Unset []string
+}
+
+type decodeState struct {
+	md   *DecodeMetadata
+	path string
+}
+
+// enter returns the converter to decode the element of the current value,
+// such as the struct field, slice index "[0]" or map key "[key]".
+func (c *Converter) enter(elem string) *Converter {
+	if c.state == nil {
+		return c
+	}
+
+	nc := *c
+	nc.state = &decodeState{md: c.state.md, path: joinPath(c.state.path, elem)}
+	return &nc
+}
+
+// DecodeWithMetadata is equal to DefaultConverter.DecodeWithMetadata(dst, src, md).
+func DecodeWithMetadata(dst, src interface{}, md *DecodeMetadata) error {
+	return DefaultConverter.DecodeWithMetadata(dst, src, md)
+}
+
+// DecodeWithMetadata is the same as Decode, but populates md
+// with the metadata of the decoding if md is not nil.
+func (c *Converter) DecodeWithMetadata(dst, src interface{}, md *DecodeMetadata) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return newError("Decode", src, reflect.TypeOf(dst), ErrUnsupported, errNotSettable)
+	}
+
+	if md == nil {
+		if !c.ErrorUnused {
+			return c.setValue(v.Elem(), src)
+		}
+		md = new(DecodeMetadata)
+	}
+
+	nc := *c
+	nc.state = &decodeState{md: md}
+	if err := nc.setValue(v.Elem(), src); err != nil {
+		return err
+	}
+
+	if c.ErrorUnused && len(md.Unused) > 0 {
+		err := errors.New("unused keys: " + strings.Join(md.Unused, ", "))
+		return newError("Decode", src, v.Type().Elem(), ErrUnusedKeys, err)
+	}
+
+	return nil
+}
+
+// WithErrorUnused returns an option to set whether to return an error
+// if some keys of the source are not used when decoding a struct.
+func WithErrorUnused(errorUnused bool) Option {
+	return func(c *Converter) { c.ErrorUnused = errorUnused }
+}
+
+// setValue sets the settable value dst to src by Set.
+func (c *Converter) setValue(dst reflect.Value, src interface{}) error {
+	return c.Set(dst.Addr().Interface(), src)
+}
+
+// setterTypes is the interfaces handled by setByInterface.
+var setterTypes = []reflect.Type{
+	reflect.TypeOf((*interface{ Set(interface{}) error })(nil)).Elem(),
+	reflect.TypeOf((*sql.Scanner)(nil)).Elem(),
+	reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*json.Unmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*flag.Value)(nil)).Elem(),
+}
+
+// isSetterType reports whether the type t implements one of
+// the interfaces handled by setByInterface.
+func isSetterType(t reflect.Type) bool {
+	for _, st := range setterTypes {
+		if t.Implements(st) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSetter reports whether the pointer to the addressable value v
+// implements one of the interfaces handled by setByInterface.
+func isSetter(v reflect.Value) bool {
+	return isSetterType(v.Addr().Type())
+}
+
+// decodeStruct decodes src into the settable struct value dst.
+func (c *Converter) decodeStruct(dst reflect.Value, src interface{}) (err error) {
+	if c.state == nil && c.ErrorUnused {
+		// Called by Set, not Decode.
+		return c.DecodeWithMetadata(dst.Addr().Interface(), src, nil)
+	}
+
+	var m map[string]interface{}
+	if src != nil {
+		v := reflect.ValueOf(src)
+		if v.Type() == dst.Type() {
+			dst.Set(v)
+			return nil
+		}
+
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		// Only decode from a map or struct, not such as a string,
+		// which is split into the key-value pairs by ToStringMap.
+		switch v.Kind() {
+		case reflect.Map, reflect.Struct, reflect.Pointer:
+		default:
+			return newError("Decode", src, dst.Type(), ErrUnsupported, nil)
+		}
+
+		if m, err = c.ToStringMap(src); err != nil {
+			return
+		}
+	}
+
+	fields, err := getStructFields(dst.Type())
+	if err != nil {
+		return newError("Decode", src, dst.Type(), nil, err)
+	}
+
+	var remain *structField
+	var used map[string]struct{}
+	for i := range fields {
+		if fields[i].remain {
+			remain = &fields[i]
+		}
+	}
+	if c.state != nil || remain != nil {
+		used = make(map[string]struct{}, len(fields))
+	}
+
+	keys := c.newKeyMatcher(m)
+	for i := range fields {
+		field := &fields[i]
+		if field.remain {
+			continue
+		}
+
+		key, value, ok := keys.match(c, field)
+		if ok && used != nil {
+			// The matched key is consumed by the field even if omitted,
+			// so it is neither unused nor collected by the remain field.
+			used[key] = struct{}{}
+		}
+
+		if ok && field.omitEmpty && isEmptyValue(reflect.ValueOf(value)) {
+			ok = false
+		}
+
+		if c.state != nil {
+			// Record the key after the omitempty decision so that
+			// the omitted and defaulted fields are reported as unset.
+			c.state.record(key, ok)
+		}
+
+		fc := field.converter(c.enter(key))
+		switch {
+		case ok:
+			fv, _ := fieldByIndex(dst, field.Index, true)
+			err = fc.setValue(fv, value)
+
+		case field.hasDefault:
+			value = field.defaultValue
+			fv, _ := fieldByIndex(dst, field.Index, true)
+			err = fc.setValue(fv, value)
+
+		default:
+			// Set the default values of the fields of the nested struct.
+			if fv, ok := fieldByIndex(dst, field.Index, false); ok && isDecodableStruct(fv) {
+				err = fc.decodeStruct(fv, nil)
+			}
+		}
+
+		if err != nil {
+			return prefixPath(wrapSetError(value, field.Type, err), field.Name)
+		}
+	}
+
+	if remain != nil {
+		if err = c.setRemain(dst, remain, m, used); err != nil {
+			return
+		}
+	}
+
+	if c.state != nil {
+		c.state.recordUnused(m, used)
+	}
+
+	return nil
+}
+
+// setRemain sets the remain field of the struct dst to the keys of m
+// not in used, and adds them into used.
+func (c *Converter) setRemain(dst reflect.Value, field *structField, m map[string]interface{}, used map[string]struct{}) error {
+	rest := make(map[string]interface{}, len(m)-len(used))
+	for key, value := range m {
+		if _, ok := used[key]; !ok {
+			rest[key] = value
+			used[key] = struct{}{}
+		}
+	}
+
+	if c.state != nil {
+		keys := make([]string, 0, len(rest))
+		for key := range rest {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		path := joinPath(c.state.path, field.key)
+		for _, key := range keys {
+			c.state.md.Keys = append(c.state.md.Keys, joinPath(path, "["+key+"]"))
+		}
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+
+	fv, _ := fieldByIndex(dst, field.Index, true)
+	if err := field.converter(c.enter(field.key)).setValue(fv, rest); err != nil {
+		return prefixPath(wrapSetError(rest, field.Type, err), field.Name)
+	}
+	return nil
+}
+
+// record records the key of the struct field, which is set from the source
+// if used is true, or else missing from the source.
+func (s *decodeState) record(key string, used bool) {
+	if used {
+		s.md.Keys = append(s.md.Keys, joinPath(s.path, key))
+	} else {
+		s.md.Unset = append(s.md.Unset, joinPath(s.path, key))
+	}
+}
+
+// recordUnused records the keys of m not in used in the sorted order.
+func (s *decodeState) recordUnused(m map[string]interface{}, used map[string]struct{}) {
+	start := len(s.md.Unused)
+	for key := range m {
+		if _, ok := used[key]; !ok {
+			s.md.Unused = append(s.md.Unused, joinPath(s.path, key))
+		}
+	}
+	sort.Strings(s.md.Unused[start:])
+}
+
+// isDecodableStruct reports whether the addressable value v is a struct
+// decoded field by field, not converted as a whole like time.Time.
+func isDecodableStruct(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type() != timeType && !isSetter(v)
+}
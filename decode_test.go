@@ -0,0 +1,365 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleDecode() {
+	type Server struct {
+		Host string
+		Port uint16
+	}
+
+	type Config struct {
+		Name    string
+		Timeout time.Duration
+		Servers []Server
+		Primary *Server
+		Labels  map[string]int
+		Extra   interface{}
+		ignored int
+	}
+
+	var config Config
+	err := Decode(&config, map[string]interface{}{
+		"Name":    "app",
+		"Timeout": "3s",
+		"Servers": []interface{}{
+			map[string]interface{}{"Host": "127.0.0.1", "Port": "80"},
+			map[interface{}]interface{}{"Host": "127.0.0.2", "Port": 81.0},
+		},
+		"Primary": map[string]string{"Host": "localhost", "Port": "8080"},
+		"Labels":  "a=1,b=2",
+		"Extra":   []int{1, 2},
+		"ignored": 1,
+	})
+
+	fmt.Println(err)
+	fmt.Println(config.Name, config.Timeout, config.Servers, *config.Primary)
+	fmt.Println(config.Labels, config.Extra, config.ignored)
+
+	err = Decode(&config, map[string]interface{}{
+		"Servers": []interface{}{
+			map[string]interface{}{"Port": "80"},
+			map[string]interface{}{"Port": "abc"},
+		},
+	})
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// app 3s [{127.0.0.1 80} {127.0.0.2 81}] {localhost 8080}
+	// map[a:1 b:2] [1 2] 0
+	// cast.ToUint64: Servers[1].Port: cannot convert string("abc") to uint64: strconv.ParseUint: parsing "abc": invalid syntax
+}
+
+type decodeInner struct {
+	Value int
+}
+
+type decodeOuter struct {
+	Inner        decodeInner
+	PInner       *decodeInner
+	Time         time.Time
+	DecodeInline fmtStringer
+	Bytes        []byte
+	Keep         string
+}
+
+func TestDecode(t *testing.T) {
+	outer := decodeOuter{Keep: "keep", PInner: &decodeInner{Value: 1}}
+	err := Decode(&outer, map[string]interface{}{
+		"Inner":        decodeInner{Value: 2},
+		"PInner":       map[string]interface{}{},
+		"Time":         1234567890,
+		"DecodeInline": 123,
+		"Bytes":        "abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := decodeOuter{
+		Inner:        decodeInner{Value: 2},
+		PInner:       &decodeInner{Value: 1},
+		Time:         time.Unix(1234567890, 0).UTC(),
+		DecodeInline: fmtStringer{"123"},
+		Bytes:        []byte("abc"),
+		Keep:         "keep",
+	}
+	if !reflect.DeepEqual(outer, expect) {
+		t.Errorf("expect %+v, but got %+v", expect, outer)
+	}
+
+	// Set delegates to Decode for the struct.
+	var inner decodeInner
+	if err := Set(&inner, struct{ Value string }{Value: "3"}); err != nil {
+		t.Error(err)
+	} else if inner.Value != 3 {
+		t.Errorf("expect %d, but got %d", 3, inner.Value)
+	}
+
+	if v, err := To[decodeInner](map[string]int{"Value": 4}); err != nil {
+		t.Error(err)
+	} else if v.Value != 4 {
+		t.Errorf("expect %d, but got %d", 4, v.Value)
+	}
+
+	if err := Decode(&inner, nil); err != nil {
+		t.Error(err)
+	} else if inner.Value != 3 {
+		t.Errorf("expect %d, but got %d", 3, inner.Value)
+	}
+
+	if err := Decode(inner, nil); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect error '%v', but got '%v'", ErrUnsupported, err)
+	}
+
+	if err := Decode(&inner, 123); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect error '%v', but got '%v'", ErrUnsupported, err)
+	}
+
+	var ce *ConversionError
+	err = Decode(&outer, map[string]interface{}{"PInner": map[string]interface{}{"Value": "x"}})
+	if !errors.As(err, &ce) {
+		t.Errorf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if ce.Path != "PInner.Value" {
+		t.Errorf("expect path '%s', but got '%s'", "PInner.Value", ce.Path)
+	}
+}
+
+func TestDecodeUnsupportedSource(t *testing.T) {
+	type S struct{ A int }
+	type myTime time.Time
+
+	var s S
+	var mt myTime
+	tests := []struct {
+		dst interface{}
+		src interface{}
+	}{
+		{&s, "abc"},
+		{&s, "A=1"},
+		{&s, []byte("A=1")},
+		{&s, 42},
+		{&s, []interface{}{1}},
+		{&mt, "2023-01-01 00:00:00"},
+	}
+
+	for _, test := range tests {
+		err := Set(test.dst, test.src)
+		expect := reflect.TypeOf(test.dst).Elem()
+
+		var ce *ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("%v: expect a *ConversionError, but got %T(%v)", test.src, err, err)
+		} else if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%v: expect ErrUnsupported, but got %v", test.src, err)
+		} else if ce.Target != expect {
+			t.Errorf("%v: expect target %s, but got %s", test.src, expect, ce.Target)
+		}
+	}
+
+	if s.A != 0 {
+		t.Errorf("expect the unchanged field, but got %d", s.A)
+	}
+
+	src := &struct{ A string }{A: "1"}
+	if err := Set(&s, src); err != nil {
+		t.Error(err)
+	} else if s.A != 1 {
+		t.Errorf("expect %d, but got %d", 1, s.A)
+	}
+}
+
+func ExampleDecodeWithMetadata() {
+	type Server struct {
+		Host string
+		Port uint16 `default:"80"`
+	}
+
+	type Config struct {
+		Name    string
+		Servers []Server
+	}
+
+	src := map[string]interface{}{
+		"Name": "app",
+		"Servers": []interface{}{
+			map[string]interface{}{"Host": "127.0.0.1", "Prot": 8080},
+		},
+		"Debug": true,
+	}
+
+	var md DecodeMetadata
+	var config Config
+	fmt.Println(DecodeWithMetadata(&config, src, &md))
+	fmt.Println(md.Keys)
+	fmt.Println(md.Unused)
+	fmt.Println(md.Unset)
+
+	err := NewConverter(WithErrorUnused(true)).Decode(&config, src)
+	fmt.Println(errors.Is(err, ErrUnusedKeys), err.(*ConversionError).Err)
+
+	// Output:
+	// <nil>
+	// [Name Servers Servers[0].Host]
+	// [Servers[0].Prot Debug]
+	// [Servers[0].Port]
+	// true unused keys: Servers[0].Prot, Debug
+}
+
+func TestDecodeMetadataOmitEmpty(t *testing.T) {
+	type S struct {
+		A int `cast:"a,omitempty" default:"7"`
+		B int `cast:"b,omitempty"`
+		C int `cast:"c"`
+	}
+
+	var s S
+	var md DecodeMetadata
+	src := map[string]interface{}{"a": 0, "b": 0, "c": 0}
+	if err := DecodeWithMetadata(&s, src, &md); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.A != 7 {
+		t.Errorf("expect a=%d, but got %d", 7, s.A)
+	}
+	if !reflect.DeepEqual(md.Keys, []string{"c"}) {
+		t.Errorf("expect keys %v, but got %v", []string{"c"}, md.Keys)
+	}
+	if !reflect.DeepEqual(md.Unset, []string{"a", "b"}) {
+		t.Errorf("expect unset %v, but got %v", []string{"a", "b"}, md.Unset)
+	}
+	if len(md.Unused) != 0 {
+		t.Errorf("expect no unused keys, but got %v", md.Unused)
+	}
+}
+
+func TestDecodeErrorUnused(t *testing.T) {
+	type S struct {
+		Inner decodeInner
+		Map   map[string]decodeInner
+	}
+
+	c := NewConverter(WithErrorUnused(true))
+
+	var s S
+	err := c.Set(&s, map[string]interface{}{
+		"Inner": map[string]interface{}{"Value": 1, "b": 2, "a": 3},
+		"Map":   map[string]interface{}{"x": map[string]interface{}{"Value": 4, "c": 5}},
+	})
+
+	var ce *ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if !errors.Is(err, ErrUnusedKeys) {
+		t.Errorf("expect kind '%v', but got '%v'", ErrUnusedKeys, ce.Kind)
+	} else if msg := "unused keys: Inner.a, Inner.b, Map[x].c"; ce.Err.Error() != msg {
+		t.Errorf("expect '%s', but got '%s'", msg, ce.Err.Error())
+	}
+
+	if err := c.Decode(&s, map[string]interface{}{"Inner": map[string]int{"Value": 1}}); err != nil {
+		t.Error(err)
+	}
+}
+
+type DecodeCommon struct {
+	Name    string
+	Timeout time.Duration `default:"3s"`
+}
+
+type DecodeLog struct {
+	Level string
+}
+
+func ExampleDecode_squash() {
+	type DB struct {
+		DecodeCommon
+		*DecodeLog
+
+		DSN   string
+		Name  string                 `cast:"db_name"` // Hide DecodeCommon.Name
+		Other map[string]interface{} `cast:",remain"`
+	}
+
+	var db DB
+	var md DecodeMetadata
+	err := DecodeWithMetadata(&db, map[string]interface{}{
+		"Name":    "common",
+		"db_name": "db",
+		"DSN":     "mysql://127.0.0.1",
+		"Level":   "info",
+		"Debug":   true,
+	}, &md)
+
+	fmt.Println(err)
+	fmt.Println(db.DecodeCommon, *db.DecodeLog, db.DSN, db.Name, db.Other)
+	fmt.Println(md.Keys, md.Unused)
+
+	// Convert the struct to a map with the squashed and remain fields.
+	db.DecodeLog = nil
+	fmt.Println(ToStringMap(db))
+
+	// Output:
+	// <nil>
+	// {common 3s} {info} mysql://127.0.0.1 db map[Debug:true]
+	// [Name Level DSN db_name Other[Debug]] []
+	// map[DSN:mysql://127.0.0.1 Debug:true Name:common Timeout:3s db_name:db] <nil>
+}
+
+type decodeEmbed struct{ Value int }
+
+func TestDecodeSquash(t *testing.T) {
+	var s1 struct {
+		decodeEmbed              // Unexported struct is squashed.
+		*DecodeLog               // Nil pointer is not allocated if no field is set.
+		Inner       DecodeInline `cast:"inner,squash"`
+		Named       DecodeLog    `cast:"named"` // Not squashed
+	}
+
+	if err := Decode(&s1, map[string]interface{}{"Value": 1, "V": 2, "named": map[string]string{"Level": "x"}}); err != nil {
+		t.Error(err)
+	} else if s1.Value != 1 || s1.DecodeLog != nil || s1.Inner.V != 2 || s1.Named.Level != "x" {
+		t.Errorf("unexpected result %+v", s1)
+	}
+
+	tests := []interface{}{
+		&struct {
+			*decodeEmbed `cast:",squash"`
+		}{},
+		&struct {
+			V int `cast:",squash"`
+		}{},
+		&struct {
+			V []string `cast:",remain"`
+		}{},
+	}
+
+	for _, dst := range tests {
+		if err := Decode(dst, nil); err == nil {
+			t.Errorf("%T: expect an error, but got nil", dst)
+		}
+	}
+}
+
+type DecodeInline struct{ V int }
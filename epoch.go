@@ -0,0 +1,324 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EpochUnit is the unit of the numeric unix timestamp.
+type EpochUnit uint8
+
+const (
+	// EpochSecond represents the unix timestamp in seconds.
+	EpochSecond EpochUnit = iota
+
+	// EpochMilli represents the unix timestamp in milliseconds.
+	EpochMilli
+
+	// EpochMicro represents the unix timestamp in microseconds.
+	EpochMicro
+
+	// EpochNano represents the unix timestamp in nanoseconds.
+	EpochNano
+
+	// EpochAuto detects the unit of the unix timestamp by its magnitude:
+	//
+	//	|v| < 1e11: => seconds, until the year 5138
+	//	|v| < 1e14: => milliseconds
+	//	|v| < 1e17: => microseconds
+	//	others: => nanoseconds
+	//
+	// It is only used to convert a number to time.Time. When converting
+	// time.Time to a number, the unit is EpochSecond instead.
+	EpochAuto
+)
+
+// WithEpochUnit returns an option to set the unit of the unix timestamp.
+func WithEpochUnit(unit EpochUnit) Option {
+	return func(c *Converter) { c.EpochUnit = unit }
+}
+
+// String returns the short name of the unit, such as "s", "ms", "us", "ns" and "auto".
+func (u EpochUnit) String() string {
+	switch u {
+	case EpochSecond:
+		return "s"
+	case EpochMilli:
+		return "ms"
+	case EpochMicro:
+		return "us"
+	case EpochNano:
+		return "ns"
+	case EpochAuto:
+		return "auto"
+	default:
+		return "unknown"
+	}
+}
+
+// detect returns the unit of the unix timestamp v if u is EpochAuto.
+func (u EpochUnit) detect(v float64) EpochUnit {
+	if u != EpochAuto {
+		return u
+	}
+
+	switch v = math.Abs(v); {
+	case v < 1e11:
+		return EpochSecond
+	case v < 1e14:
+		return EpochMilli
+	case v < 1e17:
+		return EpochMicro
+	default:
+		return EpochNano
+	}
+}
+
+// toTime converts the unix timestamp v in the unit u to time.Time.
+func (u EpochUnit) toTime(v int64) time.Time {
+	switch u {
+	case EpochMilli:
+		return time.UnixMilli(v)
+	case EpochMicro:
+		return time.UnixMicro(v)
+	case EpochNano:
+		return time.Unix(0, v)
+	default:
+		return time.Unix(v, 0)
+	}
+}
+
+// duration returns the duration of the unit u.
+func (u EpochUnit) duration() time.Duration {
+	switch u {
+	case EpochMilli:
+		return time.Millisecond
+	case EpochMicro:
+		return time.Microsecond
+	case EpochNano:
+		return time.Nanosecond
+	default:
+		return time.Second
+	}
+}
+
+// fromTime converts the time t to the unix timestamp in the unit u.
+func (u EpochUnit) fromTime(t time.Time) int64 {
+	switch u {
+	case EpochMilli:
+		return t.UnixMilli()
+	case EpochMicro:
+		return t.UnixMicro()
+	case EpochNano:
+		return t.UnixNano()
+	default:
+		return t.Unix()
+	}
+}
+
+// intToTime converts the unix timestamp v, converted from src,
+// in the epoch unit to time.Time.
+func (c *Converter) intToTime(v int64, loc *time.Location) time.Time {
+	return c.EpochUnit.detect(float64(v)).toTime(v).In(loc)
+}
+
+// uintToTime converts the unsigned unix timestamp v, converted from src,
+// in the epoch unit to time.Time.
+func (c *Converter) uintToTime(src interface{}, v uint64, loc *time.Location) (time.Time, error) {
+	i, err := c.uintToInt64(src, v)
+	if err != nil {
+		return time.Time{}.In(loc), err
+	}
+	return c.intToTime(i, loc), nil
+}
+
+// floatToTime converts the unix timestamp v, converted from src, in the epoch
+// unit to time.Time, which keeps the fractional part to the nanoseconds.
+//
+// bitSize is the bit size of the original float, that's, 32 or 64. If v has
+// the fractional part, it is formatted to the shortest decimal string to be
+// parsed, so 1700000000.123 is not affected by its binary error, such as
+// 1700000000.12299990654.
+func (c *Converter) floatToTime(src interface{}, v float64, bitSize int, loc *time.Location) (time.Time, error) {
+	if math.IsNaN(v) || v == math.Trunc(v) {
+		i, err := c.floatToInt64(src, v, timeType)
+		if err != nil {
+			return time.Time{}.In(loc), err
+		}
+		return c.EpochUnit.detect(v).toTime(i).In(loc), nil
+	}
+	return c.decimalToTime(src, strconv.FormatFloat(v, 'f', -1, bitSize), loc)
+}
+
+// decimalToTime parses the decimal string s, such as "-1700000000.123",
+// converted from src, as the unix timestamp in the epoch unit, which rounds
+// the part below the nanosecond by the rounding mode.
+func (c *Converter) decimalToTime(src interface{}, s string, loc *time.Location) (time.Time, error) {
+	intpart, frac, _ := strings.Cut(s, ".")
+	i, err := strconv.ParseInt(intpart, 10, 64)
+	if isRangeError(err) {
+		// Like the float, the fractional part of the overflowed one is dropped.
+		if i, err = c.epochOverflow(src, intpart); err == nil {
+			return c.intToTime(i, loc), nil
+		}
+	}
+	if err != nil {
+		return time.Time{}.In(loc), wrapError("TryParseTime", src, timeType, err)
+	}
+
+	unit := c.EpochUnit.detect(float64(i))
+	ns, err := c.scaleFraction(src, frac, int64(unit.duration()), intpart[0] == '-')
+	if err != nil {
+		return time.Time{}.In(loc), err
+	}
+	return unit.toTime(i).Add(time.Duration(ns)).In(loc), nil
+}
+
+// epochOverflow handles the out-of-range integer string s, converted from src,
+// as the unix timestamp by the overflow policy.
+func (c *Converter) epochOverflow(src interface{}, s string) (int64, error) {
+	wrapped := int64(wrapIntString(s, 10, 1))
+	return c.overflowInt(src, timeType, s[0] != '-', math.MinInt64, math.MaxInt64, wrapped)
+}
+
+// scaleFraction multiplies the fraction, whose digits after the decimal
+// point are frac, such as "123" for 0.123, by scale, and rounds the result
+// by the rounding mode. If neg is true, the fraction is negative.
+func (c *Converter) scaleFraction(src interface{}, frac string, scale int64, neg bool) (int64, error) {
+	if frac == "" {
+		return 0, nil
+	}
+
+	num, _ := new(big.Int).SetString(frac, 10)
+	den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(frac))), nil)
+	q, r := num.Mul(num, big.NewInt(scale)).QuoRem(num, den, new(big.Int))
+	if r.Sign() != 0 && c.Lossless {
+		return 0, precisionLossError(src, timeType)
+	}
+
+	if neg {
+		q.Neg(q)
+		r.Neg(r)
+	}
+
+	f, _ := new(big.Rat).SetFrac(r, den).Float64()
+	return c.Rounding.roundFraction(q.Int64(), f), nil
+}
+
+// isDecimalString reports whether s is a decimal string with the fractional
+// part, such as "1700000000.123" and "-1.5".
+func isDecimalString(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	intpart, frac, ok := strings.Cut(s, ".")
+	return ok && isDigits(intpart) && isDigits(frac)
+}
+
+// isFloatString reports whether s is a decimal float numeral with the optional
+// fractional part and exponent, such as "1.5", ".5", "-2." and "1.5e3",
+// but not "NaN", "Inf" or the hexadecimal float such as "0x1p4".
+func isFloatString(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp := s[i+1:]
+		if exp != "" && (exp[0] == '-' || exp[0] == '+') {
+			exp = exp[1:]
+		}
+		if !isDigits(exp) {
+			return false
+		}
+		s = s[:i]
+	}
+
+	intpart, frac, _ := strings.Cut(s, ".")
+	return (intpart == "" || isDigits(intpart)) && (frac == "" || isDigits(frac)) &&
+		(intpart != "" || frac != "")
+}
+
+// isDigits reports whether s is a non-empty string only containing the digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// timeToUnix converts the time t, converted from src, to the unix timestamp
+// in the epoch unit, which is seconds for EpochAuto.
+func (c *Converter) timeToUnix(src interface{}, t time.Time, unit EpochUnit) (int64, error) {
+	if unit == EpochAuto {
+		unit = EpochSecond
+	}
+
+	if c.Lossless && t.Nanosecond()%int(unit.duration()) != 0 {
+		return 0, precisionLossError(src, int64Type)
+	}
+	return unit.fromTime(t), nil
+}
+
+// ToUnix is equal to DefaultConverter.ToUnix(any).
+func ToUnix(any interface{}) (int64, error) { return DefaultConverter.ToUnix(any) }
+
+// ToUnixMilli is equal to DefaultConverter.ToUnixMilli(any).
+func ToUnixMilli(any interface{}) (int64, error) { return DefaultConverter.ToUnixMilli(any) }
+
+// ToUnixMicro is equal to DefaultConverter.ToUnixMicro(any).
+func ToUnixMicro(any interface{}) (int64, error) { return DefaultConverter.ToUnixMicro(any) }
+
+// ToUnixNano is equal to DefaultConverter.ToUnixNano(any).
+func ToUnixNano(any interface{}) (int64, error) { return DefaultConverter.ToUnixNano(any) }
+
+// ToUnix converts any to time.Time by ToTime, and returns the unix timestamp
+// in c.EpochUnit, which is seconds for EpochAuto.
+func (c *Converter) ToUnix(any interface{}) (int64, error) {
+	return c.toUnix(any, c.EpochUnit)
+}
+
+// ToUnixMilli is the same as ToUnix, but always returns the milliseconds.
+func (c *Converter) ToUnixMilli(any interface{}) (int64, error) {
+	return c.toUnix(any, EpochMilli)
+}
+
+// ToUnixMicro is the same as ToUnix, but always returns the microseconds.
+func (c *Converter) ToUnixMicro(any interface{}) (int64, error) {
+	return c.toUnix(any, EpochMicro)
+}
+
+// ToUnixNano is the same as ToUnix, but always returns the nanoseconds.
+func (c *Converter) ToUnixNano(any interface{}) (int64, error) {
+	return c.toUnix(any, EpochNano)
+}
+
+func (c *Converter) toUnix(any interface{}, unit EpochUnit) (int64, error) {
+	t, err := c.ToTime(any)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := c.timeToUnix(any, t, unit)
+	return v, wrapError("ToUnix", any, int64Type, err)
+}
@@ -0,0 +1,300 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ExampleWithEpochUnit() {
+	milli := DefaultConverter.With(WithEpochUnit(EpochMilli))
+	fmt.Println(milli.ToTimeInLocation(1700000000123, time.UTC))
+	fmt.Println(milli.ToInt64(time.Unix(1700000000, 123000000)))
+
+	auto := DefaultConverter.With(WithEpochUnit(EpochAuto))
+	fmt.Println(auto.ToTimeInLocation(1700000000, time.UTC))
+	fmt.Println(auto.ToTimeInLocation("1700000000123456", time.UTC))
+	fmt.Println(auto.ToTimeInLocation(uint64(1700000000123456789), time.UTC))
+
+	// Output:
+	// 2023-11-14 22:13:20.123 +0000 UTC <nil>
+	// 1700000000123 <nil>
+	// 2023-11-14 22:13:20 +0000 UTC <nil>
+	// 2023-11-14 22:13:20.123456 +0000 UTC <nil>
+	// 2023-11-14 22:13:20.123456789 +0000 UTC <nil>
+}
+
+func ExampleToUnixMilli() {
+	t := time.Date(2023, 11, 14, 22, 13, 20, 123456789, time.UTC)
+	fmt.Println(ToUnix(t))
+	fmt.Println(ToUnixMilli(t))
+	fmt.Println(ToUnixMicro(t))
+	fmt.Println(ToUnixNano(t))
+	fmt.Println(ToUnixMilli("2023-11-14T22:13:20.5Z"))
+
+	// Output:
+	// 1700000000 <nil>
+	// 1700000000123 <nil>
+	// 1700000000123456 <nil>
+	// 1700000000123456789 <nil>
+	// 1700000000500 <nil>
+}
+
+func TestEpochUnitDetect(t *testing.T) {
+	tests := []struct {
+		value  float64
+		expect EpochUnit
+	}{
+		{0, EpochSecond},
+		{1700000000, EpochSecond},
+		{-1700000000, EpochSecond},
+		{99999999999, EpochSecond},
+		{1e11, EpochMilli},
+		{1700000000123, EpochMilli},
+		{-1700000000123, EpochMilli},
+		{1700000000123456, EpochMicro},
+		{1700000000123456789, EpochNano},
+		{math.MaxInt64, EpochNano},
+	}
+
+	for _, test := range tests {
+		if unit := EpochAuto.detect(test.value); unit != test.expect {
+			t.Errorf("%v: expect %s, but got %s", test.value, test.expect, unit)
+		}
+	}
+
+	if unit := EpochMicro.detect(1); unit != EpochMicro {
+		t.Errorf("expect %s, but got %s", EpochMicro, unit)
+	}
+}
+
+func TestEpochUnit(t *testing.T) {
+	expect := time.Date(2023, 11, 14, 22, 13, 20, 123456000, time.UTC)
+	tests := []struct {
+		unit EpochUnit
+		src  interface{}
+	}{
+		{EpochMicro, int64(1700000000123456)},
+		{EpochMicro, uint64(1700000000123456)},
+		{EpochMicro, "1700000000123456"},
+		{EpochMicro, 1700000000123456.0},
+		{EpochNano, int64(1700000000123456000)},
+		{EpochAuto, int64(1700000000123456)},
+		{EpochAuto, int64(1700000000123456000)},
+	}
+
+	for _, test := range tests {
+		c := NewConverter(WithEpochUnit(test.unit))
+		if v, err := c.ToTimeInLocation(test.src, time.UTC); err != nil {
+			t.Errorf("%s %T(%v): %v", test.unit, test.src, test.src, err)
+		} else if !v.Equal(expect) {
+			t.Errorf("%s %T(%v): expect %s, but got %s", test.unit, test.src, test.src, expect, v)
+		}
+
+		var v time.Time
+		if err := c.Set(&v, test.src); err != nil {
+			t.Errorf("%s %T(%v): %v", test.unit, test.src, test.src, err)
+		} else if !v.Equal(expect) {
+			t.Errorf("%s %T(%v): expect %s, but got %s", test.unit, test.src, test.src, expect, v)
+		}
+	}
+
+	type Milli int64
+	c := NewConverter(WithEpochUnit(EpochMilli))
+	if v, err := c.ToTime(Milli(-1500)); err != nil {
+		t.Error(err)
+	} else if !v.Equal(time.Unix(-2, 500000000)) {
+		t.Errorf("expect %s, but got %s", time.Unix(-2, 500000000), v)
+	}
+
+	if _, err := c.ToTime(uint64(math.MaxUint64)); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+}
+
+func TestTimeToUnix(t *testing.T) {
+	tm := time.Unix(1700000000, 123456789)
+	tests := []struct {
+		unit   EpochUnit
+		expect int64
+	}{
+		{EpochSecond, 1700000000},
+		{EpochMilli, 1700000000123},
+		{EpochMicro, 1700000000123456},
+		{EpochNano, 1700000000123456789},
+		{EpochAuto, 1700000000},
+	}
+
+	for _, test := range tests {
+		c := NewConverter(WithEpochUnit(test.unit))
+		if v, err := c.ToInt64(tm); err != nil {
+			t.Errorf("%s: %v", test.unit, err)
+		} else if v != test.expect {
+			t.Errorf("%s: expect %d, but got %d", test.unit, test.expect, v)
+		}
+
+		if v, err := c.ToUnix(tm); err != nil {
+			t.Errorf("%s: %v", test.unit, err)
+		} else if v != test.expect {
+			t.Errorf("%s: expect %d, but got %d", test.unit, test.expect, v)
+		}
+	}
+
+	c := NewConverter(WithEpochUnit(EpochMilli), WithLossless(true))
+	if _, err := c.ToInt64(tm); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("expect ErrPrecisionLoss, but got %v", err)
+	}
+	if _, err := c.ToUnixMilli(time.Unix(0, 1)); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("expect ErrPrecisionLoss, but got %v", err)
+	}
+	if v, err := c.ToUnixNano(time.Unix(0, 1)); err != nil {
+		t.Error(err)
+	} else if v != 1 {
+		t.Errorf("expect %d, but got %d", 1, v)
+	}
+
+	if _, err := ToUnix([]int{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect ErrUnsupported, but got %v", err)
+	}
+}
+
+func ExampleTryParseTime_decimal() {
+	fmt.Println(TryParseTime("1700000000.123", time.UTC))
+	fmt.Println(TryParseTime("-1.5", time.UTC))
+	fmt.Println(ToTimeInLocation(1700000000.123, time.UTC))
+
+	// Output:
+	// 2023-11-14 22:13:20.123 +0000 UTC <nil>
+	// 1969-12-31 23:59:58.5 +0000 UTC <nil>
+	// 2023-11-14 22:13:20.123 +0000 UTC <nil>
+}
+
+func TestFractionalEpoch(t *testing.T) {
+	tests := []struct {
+		unit   EpochUnit
+		src    interface{}
+		expect time.Time
+	}{
+		{EpochSecond, 1700000000.123, time.Unix(1700000000, 123000000)},
+		{EpochSecond, -1700000000.123, time.Unix(-1700000001, 877000000)},
+		{EpochSecond, float32(0.25), time.Unix(0, 250000000)},
+		{EpochSecond, float32(1658555776), time.Unix(1658555776, 0)},
+		{EpochSecond, -0.5, time.Unix(-1, 500000000)},
+		{EpochSecond, "1700000000.123456789", time.Unix(1700000000, 123456789)},
+		{EpochSecond, "+1700000000.1", time.Unix(1700000000, 100000000)},
+		{EpochSecond, "-0.000000001", time.Unix(0, -1)},
+		{EpochSecond, []byte("1.0000000004"), time.Unix(1, 0)},
+		{EpochMilli, 1700000000123.5, time.Unix(1700000000, 123500000)},
+		{EpochMilli, "1700000000123.456789", time.Unix(1700000000, 123456789)},
+		{EpochMicro, "-1.5", time.Unix(0, -1500)},
+		{EpochNano, "1.4", time.Unix(0, 1)},
+		{EpochAuto, 1700000000123.25, time.Unix(1700000000, 123250000)},
+		{EpochAuto, "1700000000123456.5", time.Unix(1700000000, 123456500)},
+	}
+
+	for _, test := range tests {
+		c := NewConverter(WithEpochUnit(test.unit))
+		if v, err := c.ToTimeInLocation(test.src, time.UTC); err != nil {
+			t.Errorf("%s %T(%v): %v", test.unit, test.src, test.src, err)
+		} else if !v.Equal(test.expect) {
+			t.Errorf("%s %T(%v): expect %s, but got %s", test.unit, test.src, test.src, test.expect, v)
+		}
+	}
+
+	c := NewConverter(WithRounding(RoundFloor))
+	if v, err := c.ToTime("-0.0000000005"); err != nil {
+		t.Error(err)
+	} else if expect := time.Unix(0, -1); !v.Equal(expect) {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+
+	c = NewConverter(WithLossless(true))
+	if v, err := c.ToTime(1234567890.5); err != nil {
+		t.Error(err)
+	} else if expect := time.Unix(1234567890, 500000000); !v.Equal(expect) {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+	if _, err := c.ToTime("1.0000000001"); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("expect ErrPrecisionLoss, but got %v", err)
+	}
+
+	if _, err := ToTime("99999999999999999999.5"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+	if _, err := ToTime(math.Inf(1)); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+}
+
+func TestIsDecimalString(t *testing.T) {
+	for _, s := range []string{"1.5", "-1.5", "+0.0", "1700000000.123"} {
+		if !isDecimalString(s) {
+			t.Errorf("expect '%s' to be a decimal string", s)
+		}
+	}
+
+	for _, s := range []string{"", "1", "1.", ".5", "-.5", "--1.5", "1.5.0", "1e5", "1.5e3", "2006.01.02"} {
+		if isDecimalString(s) {
+			t.Errorf("unexpect '%s' to be a decimal string", s)
+		}
+	}
+}
+
+func TestIsFloatString(t *testing.T) {
+	for _, s := range []string{"1", "1.5", "-1.5", "+0.0", "1.", ".5", "-.5", "1e5", "1.5E-3", "+2.e+10"} {
+		if !isFloatString(s) {
+			t.Errorf("expect '%s' to be a float string", s)
+		}
+	}
+
+	for _, s := range []string{"", ".", "-", "e5", "1e", "1e+", "--1.5", "1.5.0",
+		"NaN", "Inf", "-Inf", "Infinity", "0x1p4", "0x10", "1_000.5"} {
+		if isFloatString(s) {
+			t.Errorf("unexpect '%s' to be a float string", s)
+		}
+	}
+}
+
+func TestEpochStringOverflow(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	for _, s := range []string{"99999999999999999999", "-99999999999999999999", "99999999999999999999.5"} {
+		v, err := TryParseTime(s, loc)
+		if !errors.Is(err, ErrOverflow) {
+			t.Errorf("%s: expect ErrOverflow, but got %v", s, err)
+		} else if !v.IsZero() || v.Location() != loc {
+			t.Errorf("%s: expect the zero time in %s, but got %s", s, loc, v)
+		}
+	}
+
+	c := NewConverter(WithOverflow(OverflowSaturate), WithEpochUnit(EpochNano))
+	for _, s := range []string{"99999999999999999999", "99999999999999999999.5"} {
+		if v, err := c.TryParseTime(s, time.UTC); err != nil {
+			t.Errorf("%s: %v", s, err)
+		} else if v.UnixNano() != math.MaxInt64 {
+			t.Errorf("%s: expect %d, but got %d", s, int64(math.MaxInt64), v.UnixNano())
+		}
+	}
+
+	c = NewConverter(WithOverflow(OverflowWrap), WithEpochUnit(EpochSecond))
+	if v, err := c.TryParseTime("18446744073709551617", time.UTC); err != nil {
+		t.Error(err)
+	} else if v.Unix() != 1 {
+		t.Errorf("expect %d, but got %d", 1, v.Unix())
+	}
+}
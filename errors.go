@@ -0,0 +1,223 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Define the kinds of the conversion errors, which can be used
+// by errors.Is to check the kind of a *ConversionError.
+var (
+	// ErrUnsupported is returned when the source type is not supported
+	// to be converted to the target type.
+	ErrUnsupported = errors.New("unsupported conversion")
+
+	// ErrSyntax is returned when the source string has an invalid syntax
+	// for the target type.
+	ErrSyntax = errors.New("invalid syntax")
+
+	// ErrOverflow is returned when the source value overflows the target type.
+	ErrOverflow = errors.New("value out of range")
+
+	// ErrNegative is returned when converting a negative to an unsigned integer.
+	ErrNegative = errors.New("negative value")
+
+	// ErrPrecisionLoss is returned in the lossless mode when a conversion
+	// would lose the information, such as converting 1.5 to int64.
+	ErrPrecisionLoss = errors.New("precision loss")
+
+	// ErrLengthMismatch is returned when the number of the source elements
+	// is not equal to the length of the target array.
+	ErrLengthMismatch = errors.New("length mismatch")
+
+	// ErrUnusedKeys is returned by Decode when ErrorUnused is enabled
+	// and some keys of the source are not decoded into the struct.
+	ErrUnusedKeys = errors.New("unused keys")
+)
+
+var errStrict = errors.New("not allowed in the strict mode")
+
+var (
+	boolType     = reflect.TypeOf(false)
+	stringType   = reflect.TypeOf("")
+	intType      = reflect.TypeOf(int(0))
+	int8Type     = reflect.TypeOf(int8(0))
+	int16Type    = reflect.TypeOf(int16(0))
+	int32Type    = reflect.TypeOf(int32(0))
+	int64Type    = reflect.TypeOf(int64(0))
+	uintType     = reflect.TypeOf(uint(0))
+	uint8Type    = reflect.TypeOf(uint8(0))
+	uint16Type   = reflect.TypeOf(uint16(0))
+	uint32Type   = reflect.TypeOf(uint32(0))
+	uint64Type   = reflect.TypeOf(uint64(0))
+	uintptrType  = reflect.TypeOf(uintptr(0))
+	float32Type  = reflect.TypeOf(float32(0))
+	float64Type  = reflect.TypeOf(float64(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// ConversionError represents an error occurred when converting a value
+// from one type to another.
+type ConversionError struct {
+	Op     string       // The operation, such as "ToInt64", "TryParseTime", "Set".
+	Value  interface{}  // The source value to be converted.
+	Source reflect.Type // The type of the source value, which is nil if Value is nil.
+	Target reflect.Type // The target type.
+
+	// Path is the location of the failed value in the source container,
+	// such as "[1]" for a slice element, which is empty for a scalar.
+	Path string
+
+	// Kind is one of ErrUnsupported, ErrSyntax, ErrOverflow, ErrNegative,
+	// ErrPrecisionLoss, ErrLengthMismatch and ErrUnusedKeys, or nil if unknown.
+	Kind error
+
+	// Err is the underlying cause, such as *strconv.NumError, which may be nil.
+	Err error
+}
+
+func newError(op string, src interface{}, target reflect.Type, kind, err error) *ConversionError {
+	return &ConversionError{
+		Op:     op,
+		Value:  src,
+		Source: reflect.TypeOf(src),
+		Target: target,
+		Kind:   kind,
+		Err:    err,
+	}
+}
+
+// wrapError wraps the error occurred when converting src to target
+// as a *ConversionError, and returns it as it is if it is already.
+//
+// If err is not a *ConversionError, it is considered as the error
+// returned by the parser, such as strconv.ParseInt.
+func wrapError(op string, src interface{}, target reflect.Type, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var ce *ConversionError
+	if errors.As(err, &ce) {
+		return err
+	}
+
+	kind := ErrSyntax
+	if errors.Is(err, strconv.ErrRange) {
+		kind = ErrOverflow
+	}
+	return newError(op, src, target, kind, err)
+}
+
+// prefixPath returns a copy of the *ConversionError err whose path is
+// prefixed with elem, such as "[1]" or "Name", or err itself if it is not.
+func prefixPath(err error, elem string) error {
+	ce, ok := err.(*ConversionError)
+	if !ok {
+		return err
+	}
+
+	nce := *ce
+	nce.Path = joinPath(elem, ce.Path)
+	return &nce
+}
+
+// joinPath joins the paths, such as "a" and "b" to "a.b", "a" and "[0]" to "a[0]".
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case path[0] == '[':
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
+
+// opOf returns the name of the operation converting a value to the type t.
+func opOf(t reflect.Type) string {
+	switch t {
+	case timeType:
+		return "ToTimeInLocation"
+	case durationType:
+		return "ToDuration"
+	case boolType, stringType, intType, int8Type, int16Type, int32Type, int64Type,
+		uintType, uint8Type, uint16Type, uint32Type, uint64Type, float32Type, float64Type:
+		name := t.Name()
+		return "To" + strings.ToUpper(name[:1]) + name[1:]
+	default:
+		return "Set"
+	}
+}
+
+// Error implements the interface error.
+func (e *ConversionError) Error() string {
+	var b strings.Builder
+	b.WriteString("cast.")
+	b.WriteString(e.Op)
+	if e.Path != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Path)
+	}
+	b.WriteString(": cannot convert ")
+	switch v := e.Value.(type) {
+	case nil:
+		b.WriteString("nil")
+	case string:
+		fmt.Fprintf(&b, "string(%q)", v)
+	default:
+		fmt.Fprintf(&b, "%T(%v)", v, v)
+	}
+
+	if e.Target != nil {
+		b.WriteString(" to ")
+		b.WriteString(e.Target.String())
+	}
+
+	switch {
+	case e.Err != nil:
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	case e.Kind != nil:
+		b.WriteString(": ")
+		b.WriteString(e.Kind.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the kind and the underlying cause of the error,
+// so that errors.Is and errors.As can check both of them.
+func (e *ConversionError) Unwrap() []error {
+	switch {
+	case e.Kind == nil && e.Err == nil:
+		return nil
+	case e.Kind == nil:
+		return []error{e.Err}
+	case e.Err == nil:
+		return []error{e.Kind}
+	default:
+		return []error{e.Kind, e.Err}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func ExampleConversionError() {
+	_, err := ToInt64("abc")
+	fmt.Println(err)
+
+	var ce *ConversionError
+	if errors.As(err, &ce) {
+		fmt.Println(ce.Op, ce.Value, ce.Source, ce.Target)
+	}
+
+	var ne *strconv.NumError
+	fmt.Println(errors.Is(err, ErrSyntax), errors.As(err, &ne))
+
+	// Output:
+	// cast.ToInt64: cannot convert string("abc") to int64: strconv.ParseInt: parsing "abc": invalid syntax
+	// ToInt64 abc string int64
+	// true true
+}
+
+func TestConversionError(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     func() error
+		kind   error
+		op     string
+		target reflect.Type
+	}{
+		{"bool", func() error { _, err := ToBool("abc"); return err }, ErrSyntax, "ToBool", boolType},
+		{"bool", func() error { _, err := ToBool([]int{}); return err }, ErrUnsupported, "ToBool", boolType},
+		{"string", func() error { _, err := ToString(struct{}{}); return err }, ErrUnsupported, "ToString", stringType},
+		{"int64", func() error { _, err := ToInt64("99999999999999999999"); return err }, ErrOverflow, "ToInt64", int64Type},
+		{"uint64", func() error { _, err := ToUint64(-1); return err }, ErrNegative, "ToUint64", uint64Type},
+		{"uint64", func() error { _, err := ToUint64([]byte("x")); return err }, ErrSyntax, "ToUint64", uint64Type},
+		{"float64", func() error { _, err := ToFloat64(map[string]int{}); return err }, ErrUnsupported, "ToFloat64", float64Type},
+		{"duration", func() error { _, err := ToDuration("1x"); return err }, ErrSyntax, "ToDuration", durationType},
+		{"time", func() error { _, err := ToTime("abc"); return err }, ErrSyntax, "TryParseTime", timeType},
+		{"time", func() error { _, err := ToTime(true); return err }, ErrUnsupported, "ToTimeInLocation", timeType},
+		{"int8", func() error { var v int8; return Set(&v, 128) }, ErrOverflow, "ToInt8", int8Type},
+		{"chan", func() error { var v chan int; return Set(&v, 1) }, ErrUnsupported, "Set", reflect.TypeOf(make(chan int))},
+		{"nopointer", func() error { return Set(1, 1) }, ErrUnsupported, "Set", intType},
+		{"lossless", func() error { _, err := DefaultConverter.With(WithLossless(true)).ToInt64(1.5); return err }, ErrPrecisionLoss, "ToInt64", int64Type},
+	}
+
+	for _, test := range tests {
+		err := test.fn()
+
+		var ce *ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("%s: expect a *ConversionError, but got %T(%v)", test.name, err, err)
+			continue
+		}
+
+		if !errors.Is(err, test.kind) {
+			t.Errorf("%s: expect kind '%v', but got '%v'", test.name, test.kind, ce.Kind)
+		}
+		if ce.Op != test.op {
+			t.Errorf("%s: expect op '%s', but got '%s'", test.name, test.op, ce.Op)
+		}
+		if ce.Target != test.target {
+			t.Errorf("%s: expect target '%v', but got '%v'", test.name, test.target, ce.Target)
+		}
+		if ce.Source != reflect.TypeOf(ce.Value) {
+			t.Errorf("%s: expect source '%v', but got '%v'", test.name, reflect.TypeOf(ce.Value), ce.Source)
+		}
+	}
+}
+
+type errSetter struct{}
+
+func (errSetter) Set(interface{}) error { return errors.New("test") }
+
+func TestSetUserError(t *testing.T) {
+	var v errSetter
+	err := Set(&v, 1)
+
+	var ce *ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expect a *ConversionError, but got %T(%v)", err, err)
+	}
+
+	if ce.Op != "Set" || ce.Kind != nil || ce.Err == nil || ce.Err.Error() != "test" {
+		t.Errorf("unexpected error %+v", ce)
+	}
+	if ce.Target != reflect.TypeOf(v) {
+		t.Errorf("expect target '%v', but got '%v'", reflect.TypeOf(v), ce.Target)
+	}
+
+	if err := Set(&v, time.Second); err.Error() != "cast.Set: cannot convert time.Duration(1s) to cast.errSetter: test" {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
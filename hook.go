@@ -0,0 +1,183 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrSkipHook is returned by a Hook to indicate that it does not handle
+// the conversion, which falls through to the next hook or the builtin rules.
+var ErrSkipHook = errors.New("skip the hook")
+
+// Hook is used to set the settable value dst to src for the specific types.
+//
+// If not handling the conversion, it should return ErrSkipHook.
+type Hook func(dst reflect.Value, src interface{}) error
+
+type hookKey struct {
+	src reflect.Type // nil means any source type
+	dst reflect.Type
+}
+
+// interfaceSource reports whether the hooks for the source type t
+// are used for the types implementing it, that's, t is a non-empty
+// interface type. The empty interface type is normalized to nil.
+func interfaceSource(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Interface && t.NumMethod() > 0
+}
+
+// RegisterHook is equal to DefaultConverter.RegisterHook(srcType, dstType, hook).
+//
+// Like other modifications of DefaultConverter, it should be called only
+// during the initialization, such as in the init function, because it is
+// not synchronized with the concurrent conversions, such as Set and To.
+func RegisterHook(srcType, dstType reflect.Type, hook Hook) {
+	DefaultConverter.RegisterHook(srcType, dstType, hook)
+}
+
+// RegisterHook registers the hook to convert the value of srcType
+// to the value of dstType, which replaces the old one if existed.
+// If srcType is a non-empty interface type, the hook is used for all the
+// source types implementing it, which is tried after the hook for the exact
+// source type, and the hooks for different interfaces are tried in the order
+// of registration. If srcType is nil or the empty interface type, the hook
+// is used for all the source types, which is tried at last.
+// If hook is nil, unregister it.
+//
+// The hooks are tried by Set, Decode and the functions based on them,
+// such as To, ToSlice and ToMap, before the builtin rules.
+// But they are not used by the ToXXX functions, such as ToInt64,
+// which have their own hooks like ToInt64Hook.
+//
+// The registration is not safe for the concurrent use with the conversions
+// of c, so the hooks should be registered before c is used, or registered
+// to a clone of c, see Clone.
+func (c *Converter) RegisterHook(srcType, dstType reflect.Type, hook Hook) {
+	if dstType == nil {
+		panic("cast.RegisterHook: the destination type must not be nil")
+	}
+
+	if srcType != nil && srcType.Kind() == reflect.Interface && !interfaceSource(srcType) {
+		srcType = nil
+	}
+
+	key := hookKey{src: srcType, dst: dstType}
+	_, exist := c.hooks[key]
+	if hook == nil {
+		if exist {
+			delete(c.hooks, key)
+			if interfaceSource(srcType) {
+				c.removeInterface(srcType, dstType)
+			}
+		}
+		return
+	}
+
+	if c.hooks == nil {
+		c.hooks = make(map[hookKey]Hook, 4)
+	}
+	c.hooks[key] = hook
+
+	if !exist && interfaceSource(srcType) {
+		if c.ifaces == nil {
+			c.ifaces = make(map[reflect.Type][]reflect.Type, 4)
+		}
+		c.ifaces[dstType] = append(c.ifaces[dstType], srcType)
+	}
+}
+
+func (c *Converter) removeInterface(srcType, dstType reflect.Type) {
+	types := c.ifaces[dstType]
+	for i, t := range types {
+		if t == srcType {
+			types = append(types[:i:i], types[i+1:]...)
+			break
+		}
+	}
+
+	if len(types) == 0 {
+		delete(c.ifaces, dstType)
+	} else {
+		c.ifaces[dstType] = types
+	}
+}
+
+// RegisterHookFunc registers a hook for the converter c to convert
+// the value of the type S to the type D by the function f.
+//
+// If S is an interface type, the hook is used for all the source types
+// implementing it, and returns ErrSkipHook for others.
+// See Converter.RegisterHook, which also has the same concurrency restriction.
+func RegisterHookFunc[S, D any](c *Converter, f func(src S) (D, error)) {
+	stype := reflect.TypeOf((*S)(nil)).Elem()
+	dtype := reflect.TypeOf((*D)(nil)).Elem()
+
+	c.RegisterHook(stype, dtype, func(dst reflect.Value, src interface{}) error {
+		s, ok := src.(S)
+		if !ok {
+			return ErrSkipHook
+		}
+
+		d, err := f(s)
+		if err == nil {
+			dst.Set(reflect.ValueOf(&d).Elem())
+		}
+		return err
+	})
+}
+
+// runHook runs the hook registered for the types of dst and src.
+//
+// If no hook handles it, return (false, nil).
+func (c *Converter) runHook(dst reflect.Value, src interface{}) (handled bool, err error) {
+	if len(c.hooks) == 0 || !dst.CanSet() {
+		return
+	}
+
+	dtype := dst.Type()
+	if stype := reflect.TypeOf(src); stype != nil {
+		if handled, err = c.tryHook(hookKey{src: stype, dst: dtype}, dst, src); handled {
+			return
+		}
+
+		for _, itype := range c.ifaces[dtype] {
+			if !stype.Implements(itype) {
+				continue
+			}
+			if handled, err = c.tryHook(hookKey{src: itype, dst: dtype}, dst, src); handled {
+				return
+			}
+		}
+	}
+	return c.tryHook(hookKey{dst: dtype}, dst, src)
+}
+
+func (c *Converter) tryHook(key hookKey, dst reflect.Value, src interface{}) (handled bool, err error) {
+	hook, ok := c.hooks[key]
+	if !ok {
+		return false, nil
+	}
+
+	switch err = hook(dst, src); err {
+	case ErrSkipHook:
+		return false, nil
+	case nil:
+		return true, nil
+	default:
+		return true, wrapSetError(src, dst.Type(), err)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelError
+)
+
+func ExampleRegisterHookFunc() {
+	c := NewConverter()
+	RegisterHookFunc(c, func(src string) (logLevel, error) {
+		switch strings.ToLower(src) {
+		case "debug":
+			return levelDebug, nil
+		case "info":
+			return levelInfo, nil
+		case "error":
+			return levelError, nil
+		default:
+			return 0, fmt.Errorf("unknown log level '%s'", src)
+		}
+	})
+
+	type Config struct {
+		Level  logLevel
+		Levels []logLevel
+		PLevel *logLevel
+	}
+
+	var config Config
+	err := c.Decode(&config, map[string]interface{}{
+		"Level":  "Error",
+		"Levels": "debug,info",
+		"PLevel": 1, // Not string, fall through to the builtin rules.
+	})
+	fmt.Println(err, config.Level, config.Levels, *config.PLevel)
+
+	err = c.Set(&config.Level, "warn")
+	fmt.Println(err)
+
+	// Output:
+	// <nil> 2 [0 1] 1
+	// cast.Set: cannot convert string("warn") to cast.logLevel: unknown log level 'warn'
+}
+
+func TestRegisterHook(t *testing.T) {
+	c := NewConverter()
+	stringType := reflect.TypeOf("")
+	levelType := reflect.TypeOf(levelDebug)
+
+	var calls []string
+	c.RegisterHook(nil, levelType, func(dst reflect.Value, src interface{}) error {
+		calls = append(calls, "any")
+		if src == nil {
+			dst.SetInt(int64(levelInfo))
+			return nil
+		}
+		return ErrSkipHook
+	})
+	c.RegisterHook(stringType, levelType, func(dst reflect.Value, src interface{}) error {
+		calls = append(calls, "string")
+		return ErrSkipHook
+	})
+
+	var level logLevel
+	if err := c.Set(&level, "2"); err != nil {
+		t.Error(err)
+	} else if level != levelError {
+		t.Errorf("expect %d, but got %d", levelError, level)
+	} else if expect := []string{"string", "any"}; !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expect calls %v, but got %v", expect, calls)
+	}
+
+	if err := c.Set(reflect.ValueOf(&level), nil); err != nil {
+		t.Error(err)
+	} else if level != levelInfo {
+		t.Errorf("expect %d, but got %d", levelInfo, level)
+	}
+
+	// The cloned converter has the independent hooks.
+	nc := c.Clone()
+	nc.RegisterHook(nil, levelType, nil)
+	nc.RegisterHook(stringType, levelType, func(dst reflect.Value, src interface{}) error {
+		return errors.New("test")
+	})
+
+	if err := c.Set(&level, "0"); err != nil {
+		t.Error(err)
+	} else if level != levelDebug {
+		t.Errorf("expect %d, but got %d", levelDebug, level)
+	}
+
+	var ce *ConversionError
+	if err := nc.Set(&level, "1"); !errors.As(err, &ce) {
+		t.Errorf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if ce.Target != levelType || ce.Err.Error() != "test" {
+		t.Errorf("unexpected error %+v", ce)
+	}
+
+	if err := nc.Set(&level, nil); err != nil {
+		t.Error(err)
+	} else if level != levelDebug {
+		t.Errorf("expect %d, but got %d", levelDebug, level)
+	}
+}
+
+type hookStringReader struct{ s string }
+
+func (r hookStringReader) String() string             { return r.s }
+func (r hookStringReader) Read(p []byte) (int, error) { return copy(p, r.s), io.EOF }
+
+func TestRegisterHookFuncInterface(t *testing.T) {
+	c := NewConverter()
+	RegisterHookFunc(c, func(src fmt.Stringer) (logLevel, error) {
+		return levelInfo, nil
+	})
+	RegisterHookFunc(c, func(src io.Reader) (logLevel, error) {
+		return levelError, nil
+	})
+
+	var level logLevel
+	if err := c.Set(&level, strings.NewReader("")); err != nil {
+		t.Error(err)
+	} else if level != levelError {
+		t.Errorf("expect %d, but got %d", levelError, level)
+	}
+
+	if err := c.Set(&level, time.Second); err != nil {
+		t.Error(err)
+	} else if level != levelInfo {
+		t.Errorf("expect %d, but got %d", levelInfo, level)
+	}
+
+	// Implement both, and use the first registered one.
+	if err := c.Set(&level, hookStringReader{}); err != nil {
+		t.Error(err)
+	} else if level != levelInfo {
+		t.Errorf("expect %d, but got %d", levelInfo, level)
+	}
+
+	// Unregister the fmt.Stringer hook.
+	stringerType := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	c.RegisterHook(stringerType, reflect.TypeOf(level), nil)
+	if err := c.Set(&level, hookStringReader{}); err != nil {
+		t.Error(err)
+	} else if level != levelError {
+		t.Errorf("expect %d, but got %d", levelError, level)
+	}
+
+	// Neither, fall through to the builtin rules.
+	if err := c.Set(&level, "0"); err != nil {
+		t.Error(err)
+	} else if level != levelDebug {
+		t.Errorf("expect %d, but got %d", levelDebug, level)
+	}
+}
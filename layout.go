@@ -0,0 +1,359 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xgfone/go-defaults"
+)
+
+// TryParseTimeLayout is equal to DefaultConverter.TryParseTimeLayout(value, loc, layouts...).
+func TryParseTimeLayout(value string, loc *time.Location, layouts ...string) (time.Time, string, error) {
+	return DefaultConverter.TryParseTimeLayout(value, loc, layouts...)
+}
+
+// TimeParser is used to parse a string to time.Time with a fixed list
+// of layouts, which is safe for the concurrent use.
+//
+// Different from trying the layouts in turn, it skips the layouts whose
+// length and shape, such as the position of the digits and separators,
+// cannot match the value, and tries the most recently matched layout
+// first. So the layouts should not be ambiguous, such as "01/02/2006"
+// and "02/01/2006", or else the matched layout of a value like
+// "03/04/2023" depends on the previous values.
+type TimeParser struct {
+	layouts []string
+	infos   []layoutInfo
+	last    atomic.Int32
+}
+
+// NewTimeParser returns a new time parser with the layouts.
+func NewTimeParser(layouts ...string) *TimeParser {
+	p := &TimeParser{
+		layouts: slices.Clone(layouts),
+		infos:   make([]layoutInfo, len(layouts)),
+	}
+	for i, layout := range layouts {
+		p.infos[i] = parseLayoutInfo(layout)
+	}
+	return p
+}
+
+// Layouts returns the layouts of the time parser.
+func (p *TimeParser) Layouts() []string { return slices.Clone(p.layouts) }
+
+// Parse parses the string value to time.Time in loc, and returns
+// the matched layout.
+//
+// If loc is nil, use defaults.TimeLocation instead.
+func (p *TimeParser) Parse(value string, loc *time.Location) (t time.Time, layout string, err error) {
+	if loc == nil {
+		loc = defaults.TimeLocation.Get()
+	}
+
+	last := int(p.last.Load())
+	if last < len(p.infos) && p.infos[last].match(value) {
+		if t, err = time.ParseInLocation(p.layouts[last], value, loc); err == nil {
+			return t, p.layouts[last], nil
+		}
+	}
+
+	for i := range p.infos {
+		if i == last || !p.infos[i].match(value) {
+			continue
+		}
+
+		if t, err = time.ParseInLocation(p.layouts[i], value, loc); err == nil {
+			p.last.Store(int32(i))
+			return t, p.layouts[i], nil
+		}
+	}
+
+	return time.Time{}.In(loc), "", newError("TryParseTime", value, timeType, ErrSyntax, nil)
+}
+
+// parseTimeLayouts tries to parse the string value with the layouts in turn,
+// which is used when the time parser with the layouts is not cached.
+func parseTimeLayouts(value string, loc *time.Location, layouts []string) (time.Time, string, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, layout, nil
+		}
+	}
+	return time.Time{}.In(loc), "", newError("TryParseTime", value, timeType, ErrSyntax, nil)
+}
+
+// maxTimeParsers is the maximum number of the time parsers
+// in each generation of the time parser cache.
+const maxTimeParsers = 64
+
+// timeParsers is the cache of the time parsers, so the converters
+// and call sites with the same layouts share them.
+var timeParsers = newTimeParserCache(maxTimeParsers)
+
+// timeParserCache is a cache of the time parsers keyed by the layouts.
+//
+// It has two generations. When the current generation is full,
+// it becomes the previous one and the older previous one is evicted.
+// The time parser used in the previous generation is moved back
+// to the current one, so the time parsers in use are kept.
+type timeParserCache struct {
+	seed maphash.Seed
+	max  int
+
+	lock sync.RWMutex
+	curr map[uint64]*TimeParser
+	prev map[uint64]*TimeParser
+}
+
+func newTimeParserCache(max int) *timeParserCache {
+	return &timeParserCache{
+		seed: maphash.MakeSeed(),
+		curr: make(map[uint64]*TimeParser, max),
+		max:  max,
+	}
+}
+
+// Get returns the cached time parser with the layouts, which is created
+// and cached if not existed.
+//
+// If another layout list has the same hash, return nil.
+func (c *timeParserCache) Get(layouts []string) *TimeParser {
+	var key uint64
+	for _, layout := range layouts {
+		key = key*31 + maphash.String(c.seed, layout)
+	}
+
+	c.lock.RLock()
+	p, ok := c.curr[key]
+	c.lock.RUnlock()
+
+	if !ok {
+		c.lock.Lock()
+		if p, ok = c.curr[key]; !ok {
+			if p, ok = c.prev[key]; !ok {
+				p = NewTimeParser(layouts...)
+			}
+
+			if len(c.curr) >= c.max {
+				c.prev, c.curr = c.curr, make(map[uint64]*TimeParser, c.max)
+			}
+			c.curr[key] = p
+		}
+		c.lock.Unlock()
+	}
+
+	if !slices.Equal(p.layouts, layouts) {
+		return nil // Hash collision
+	}
+	return p
+}
+
+// Define the classes of the characters in the shape of a layout.
+const (
+	shapeAny   = 0 // Any character
+	shapeDigit = 1 // '0'-'9'
+	shapeAlpha = 2 // 'a'-'z' or 'A'-'Z'
+)
+
+// layoutInfo is the information of a layout to pre-screen the values,
+// which is derived from the elements of the layout like time.Parse.
+type layoutInfo struct {
+	// shape is the classes of the characters of the prefix whose width
+	// is fixed, which is a shape class above or a literal character.
+	shape  []byte
+	fixed  bool // Whether the width of the layout has been fixed so far.
+	minLen int
+	maxLen int // -1 means unbounded.
+}
+
+// match reports whether the value may match the layout.
+func (l *layoutInfo) match(value string) bool {
+	if len(value) < l.minLen || (l.maxLen >= 0 && len(value) > l.maxLen) {
+		return false
+	}
+
+	for i, c := range l.shape {
+		switch v := value[i]; c {
+		case shapeAny:
+		case shapeDigit:
+			if v < '0' || v > '9' {
+				return false
+			}
+		case shapeAlpha:
+			if (v < 'a' || v > 'z') && (v < 'A' || v > 'Z') {
+				return false
+			}
+		default:
+			if v != c {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// add appends an element of the layout, which matches min to max
+// characters of the class in the value.
+func (l *layoutInfo) add(min, max int, class byte) {
+	if l.fixed {
+		// At least, the first min characters are of the class.
+		for i := 0; i < min; i++ {
+			l.shape = append(l.shape, class)
+		}
+		l.fixed = min == max
+	}
+
+	l.minLen += min
+	if max < 0 || l.maxLen < 0 {
+		l.maxLen = -1
+	} else {
+		l.maxLen += max
+	}
+}
+
+func parseLayoutInfo(layout string) (info layoutInfo) {
+	info.fixed = true
+	for layout != "" {
+		n, min, max, class := nextLayoutElem(layout)
+		info.add(min, max, class)
+
+		// time.Parse accepts the fractional second after the seconds
+		// even if the layout does not contain it.
+		if n == -1 {
+			n = len("05")
+			if layout[0] == '5' {
+				n = len("5")
+			}
+			info.add(0, -1, shapeAny)
+		}
+
+		layout = layout[n:]
+	}
+	return
+}
+
+// nextLayoutElem returns the length n of the first element of layout,
+// and the range and class of the characters that it matches in the value
+// when parsing, which is derived from time.Parse.
+//
+// For the seconds, n is -1.
+func nextLayoutElem(layout string) (n, min, max int, class byte) {
+	switch c := layout[0]; c {
+	case 'J':
+		switch {
+		case hasPrefix(layout, "January"):
+			return 7, 3, 9, shapeAlpha
+		case hasPrefix(layout, "Jan"):
+			return 3, 3, 3, shapeAlpha
+		}
+
+	case 'M':
+		switch {
+		case hasPrefix(layout, "Monday"):
+			return 6, 6, 9, shapeAlpha
+		case hasPrefix(layout, "Mon"):
+			return 3, 3, 3, shapeAlpha
+		case hasPrefix(layout, "MST"):
+			return 3, 3, -1, shapeAny
+		}
+
+	case '0':
+		switch {
+		case hasPrefix(layout, "002"):
+			return 3, 3, 3, shapeDigit
+		case hasPrefix(layout, "05"):
+			return -1, 2, 2, shapeDigit
+		case hasPrefix(layout, "06"): // The year may have a sign.
+			return 2, 2, 2, shapeAny
+		case len(layout) > 1 && layout[1] >= '1' && layout[1] <= '4':
+			return 2, 2, 2, shapeDigit
+		}
+
+	case '1':
+		if hasPrefix(layout, "15") {
+			return 2, 1, 2, shapeDigit
+		}
+		return 1, 1, 2, shapeDigit
+
+	case '2':
+		if hasPrefix(layout, "2006") {
+			return 4, 4, 4, shapeDigit
+		}
+		return 1, 1, 2, shapeDigit
+
+	case '3', '4':
+		return 1, 1, 2, shapeDigit
+
+	case '5':
+		return -1, 1, 2, shapeDigit
+
+	case '_':
+		switch {
+		case hasPrefix(layout, "_2006"):
+			return 1, 1, 1, '_'
+		case hasPrefix(layout, "_2"):
+			return 2, 1, 3, shapeAny
+		case hasPrefix(layout, "__2"):
+			return 3, 1, 5, shapeAny
+		}
+
+	case 'P', 'p':
+		if hasPrefix(layout, "PM") || hasPrefix(layout, "pm") {
+			return 2, 2, 2, shapeAlpha
+		}
+
+	case '-', 'Z':
+		for _, zone := range []string{"070000", "07:00:00", "0700", "07:00", "07"} {
+			if hasPrefix(layout[1:], zone) {
+				if n = len(zone) + 1; c == 'Z' {
+					return n, 1, n, shapeAny
+				}
+				return n, n, n, shapeAny
+			}
+		}
+
+	case '.', ',':
+		if len(layout) > 1 && (layout[1] == '0' || layout[1] == '9') {
+			n = 2
+			for n < len(layout) && layout[n] == layout[1] {
+				n++
+			}
+
+			if n == len(layout) || layout[n] < '0' || layout[n] > '9' {
+				if layout[1] == '9' {
+					return n, 0, -1, shapeAny
+				}
+				return n, n, n, shapeAny
+			}
+		}
+
+	case ' ':
+		// time.Parse matches a space with one or more spaces.
+		return 1, 1, -1, ' '
+	}
+
+	return 1, 1, 1, layout[0]
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
@@ -0,0 +1,285 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLayouts = []string{
+	time.Layout, time.ANSIC, time.UnixDate, time.RubyDate, time.RFC822, time.RFC822Z,
+	time.RFC850, time.RFC1123, time.RFC1123Z, time.RFC3339, time.RFC3339Nano,
+	time.Kitchen, time.Stamp, time.StampMilli, time.StampMicro, time.StampNano,
+	time.DateTime, time.DateOnly, time.TimeOnly,
+	"2006/01/02 15:04:05", "02/01/2006", "Jan _2, 2006", "2006-002", "2006 __2",
+	"Monday, January 2, 2006 3:04:05.999 pm", "20060102150405", "2006-01-02T15:04:05,000Z0700",
+	"06-1-2 15:4:5 -07", "2006.01.02", "_2006 Jan",
+}
+
+func ExampleTryParseTimeLayout() {
+	layouts := []string{time.DateOnly, time.DateTime, time.RFC3339}
+	fmt.Println(TryParseTimeLayout("2023-11-14 22:13:20", time.UTC, layouts...))
+	fmt.Println(TryParseTimeLayout("2023-11-14T22:13:20+08:00", time.UTC, layouts...))
+	fmt.Println(TryParseTimeLayout("1700000000", time.UTC, layouts...))
+
+	// Output:
+	// 2023-11-14 22:13:20 +0000 UTC 2006-01-02 15:04:05 <nil>
+	// 2023-11-14 22:13:20 +0800 +0800 2006-01-02T15:04:05Z07:00 <nil>
+	// 2023-11-14 22:13:20 +0000 UTC  <nil>
+}
+
+func TestTimeParser(t *testing.T) {
+	p := NewTimeParser(testLayouts...)
+	loc := time.FixedZone("CST", 8*3600)
+	times := []time.Time{
+		time.Date(2023, 11, 14, 22, 13, 20, 123456789, loc),
+		time.Date(2024, 2, 9, 1, 2, 3, 0, time.UTC),
+		time.Date(-123, 12, 31, 12, 0, 0, 500000000, time.FixedZone("", -5*3600-1800)),
+	}
+
+	for _, layout := range testLayouts {
+		for _, tm := range times {
+			value := tm.Format(layout)
+			expect, err := time.ParseInLocation(layout, value, loc)
+			if err != nil {
+				continue
+			}
+
+			v, matched, err := p.Parse(value, loc)
+			if err != nil {
+				t.Errorf("%s: %v", value, err)
+				continue
+			}
+
+			// The value may be matched by an earlier layout.
+			if expect2, _ := time.ParseInLocation(matched, value, loc); !v.Equal(expect2) {
+				t.Errorf("%s: expect %s, but got %s", value, expect2, v)
+			}
+			if matched == layout && !v.Equal(expect) {
+				t.Errorf("%s: expect %s, but got %s", value, expect, v)
+			}
+		}
+	}
+
+	if _, _, err := p.Parse("abc", loc); !errors.Is(err, ErrSyntax) {
+		t.Errorf("expect ErrSyntax, but got %v", err)
+	}
+
+	layouts := p.Layouts()
+	layouts[0] = ""
+	if p.Layouts()[0] != testLayouts[0] {
+		t.Error("the layouts of the parser are modified")
+	}
+}
+
+func TestTimeParserLastLayout(t *testing.T) {
+	p := NewTimeParser(time.RFC3339, time.DateTime, time.DateOnly)
+	for i := 0; i < 3; i++ {
+		if _, layout, err := p.Parse("2023-11-14", time.UTC); err != nil {
+			t.Fatal(err)
+		} else if layout != time.DateOnly {
+			t.Errorf("expect layout '%s', but got '%s'", time.DateOnly, layout)
+		} else if last := p.last.Load(); last != 2 {
+			t.Errorf("expect the last layout %d, but got %d", 2, last)
+		}
+	}
+
+	if _, layout, err := p.Parse("2023-11-14 22:13:20", time.UTC); err != nil {
+		t.Fatal(err)
+	} else if layout != time.DateTime {
+		t.Errorf("expect layout '%s', but got '%s'", time.DateTime, layout)
+	} else if last := p.last.Load(); last != 1 {
+		t.Errorf("expect the last layout %d, but got %d", 1, last)
+	}
+
+	if p1, p2 := timeParsers.Get([]string{time.DateOnly}), timeParsers.Get([]string{time.DateOnly}); p1 != p2 {
+		t.Error("expect the cached time parser")
+	}
+}
+
+func TestTimeParserCache(t *testing.T) {
+	const max = 4
+	cache := newTimeParserCache(max)
+	layouts := func(i int) []string { return []string{time.DateOnly, strconv.Itoa(i)} }
+
+	p0 := cache.Get(layouts(0))
+	for i := 1; i < max*3; i++ {
+		cache.Get(layouts(i))
+		if p := cache.Get(layouts(0)); p != p0 { // Keep using it.
+			t.Fatalf("%d: expect the cached time parser", i)
+		}
+
+		if n := len(cache.curr) + len(cache.prev); n > max*2 {
+			t.Fatalf("%d: expect at most %d cached time parsers, but got %d", i, max*2, n)
+		}
+	}
+
+	// Evict the unused ones.
+	for _, p := range cache.curr {
+		if p.layouts[1] == "1" {
+			t.Error("expect the unused time parser to be evicted")
+		}
+	}
+	for _, p := range cache.prev {
+		if p.layouts[1] == "1" {
+			t.Error("expect the unused time parser to be evicted")
+		}
+	}
+
+	// Hash collision
+	var key uint64
+	for _, layout := range layouts(0) {
+		key = key*31 + maphash.String(cache.seed, layout)
+	}
+	cache.curr[key] = NewTimeParser(time.DateTime)
+	if p := cache.Get(layouts(0)); p != nil {
+		t.Errorf("expect nil for the hash collision, but got %v", p.Layouts())
+	}
+}
+
+func TestParseTimeLayouts(t *testing.T) {
+	tm, layout, err := parseTimeLayouts("2023-11-14", time.UTC, []string{time.DateTime, time.DateOnly})
+	if err != nil {
+		t.Fatal(err)
+	} else if layout != time.DateOnly || tm.Format(time.DateOnly) != "2023-11-14" {
+		t.Errorf("unexpected time '%s' by the layout '%s'", tm, layout)
+	}
+
+	tm, _, err = parseTimeLayouts("abc", time.UTC, []string{time.DateOnly})
+	if !errors.Is(err, ErrSyntax) {
+		t.Errorf("expect ErrSyntax, but got %v", err)
+	} else if !tm.IsZero() || tm.Location() != time.UTC {
+		t.Errorf("expect the zero time in UTC, but got %s", tm)
+	}
+}
+
+func TestLayoutInfo(t *testing.T) {
+	// The values accepted by time.Parse, but different from time.Format.
+	accepts := []struct {
+		layout string
+		value  string
+	}{
+		{time.DateTime, "2023-11-14 2:13:20"},
+		{time.DateTime, "2023-11-14   22:13:20"},
+		{time.DateTime, "2023-11-14 22:13:20.123456789"},
+		{time.DateTime, "2023-11-14 22:13:20,5"},
+		{"06-01-02", "-1-11-14"},
+		{time.RFC3339, "2023-11-14T22:13:20Z"},
+		{time.RFC3339, "2023-11-14T22:13:20.5+08:00"},
+		{time.RFC3339Nano, "2023-11-14T22:13:20Z"},
+		{time.Stamp, "Nov  4 22:13:20"},
+		{time.Stamp, "Nov 4 22:13:20"},
+		{time.Stamp, "nov 14 22:13:20"},
+		{time.UnixDate, "Tue Nov 14 22:13:20 GMT+8 2023"},
+		{time.RFC1123, "Tue, 14 Nov 2023 22:13:20 ChST"},
+		{"January 2", "May 2"},
+		{"January 2", "September 12"},
+		{"Monday", "Wednesday"},
+		{"2006 __2", "2023   1"},
+		{"2006 __2", "2023 123"},
+		{"3:04pm", "1:02am"},
+		{"15:04:05.000", "22:13:20,123"},
+	}
+	for _, test := range accepts {
+		if _, err := time.Parse(test.layout, test.value); err != nil {
+			t.Errorf("%s: unexpected parse error: %v", test.value, err)
+		}
+
+		info := parseLayoutInfo(test.layout)
+		if !info.match(test.value) {
+			t.Errorf("%s: expect to match the layout '%s'", test.value, test.layout)
+		}
+	}
+
+	rejects := []struct {
+		layout string
+		value  string
+	}{
+		{time.DateOnly, "2023-11-14 22:13:20"},
+		{time.DateOnly, "2023/11/14"},
+		{time.DateTime, "2023-11-14"},
+		{time.DateOnly, "-023-11-14"},
+		{time.DateTime, "2023-11-14T22:13:20Z"},
+		{time.RFC3339, "2023-11-14 22:13:20"},
+		{time.Kitchen, "22:13:20"},
+		{time.Stamp, "2023 14 22:13:20"},
+		{"20060102", "2023111"},
+	}
+	for _, test := range rejects {
+		info := parseLayoutInfo(test.layout)
+		if info.match(test.value) {
+			t.Errorf("%s: unexpect to match the layout '%s'", test.value, test.layout)
+		}
+	}
+}
+
+func benchmarkLayouts() []string {
+	layouts := append([]string(nil), testLayouts...)
+	for i, layout := range layouts {
+		if layout == time.DateTime {
+			layouts = append(layouts[:i], layouts[i+1:]...)
+			break
+		}
+	}
+	return append(layouts, time.DateTime)
+}
+
+func BenchmarkTryParseTime(b *testing.B) {
+	layouts := benchmarkLayouts()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := TryParseTime("2023-11-14 22:13:20", time.UTC, layouts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTryParseTimeMixed(b *testing.B) {
+	layouts := benchmarkLayouts()
+	values := []string{"2023-11-14 22:13:20", "2023-11-14T22:13:20Z", "Nov 14 22:13:20", "2023-11-14"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := TryParseTime(values[i%len(values)], time.UTC, layouts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTryParseTimeInTurn is the baseline, which tries the layouts in turn.
+func BenchmarkTryParseTimeInTurn(b *testing.B) {
+	layouts := benchmarkLayouts()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var ok bool
+		for _, layout := range layouts {
+			if _, err := time.ParseInLocation(layout, "2023-11-14 22:13:20", time.UTC); err == nil {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			b.Fatal(strings.Join(layouts, "\n"))
+		}
+	}
+}
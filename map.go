@@ -0,0 +1,205 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ToMap is equal to ToMapWith[K, V](DefaultConverter, src).
+func ToMap[K comparable, V any](src interface{}) (dst map[K]V, err error) {
+	return ToMapWith[K, V](DefaultConverter, src)
+}
+
+// ToMapWith converts src to a map whose keys and values are converted
+// to the types K and V by c.Set.
+//
+// src may be one of the types as follow:
+//
+//	nil: => nil
+//	~string, []byte: => "k1=v1,k2=v2", split by the separator, and trim the spaces
+//	map: => convert each key and value in turn
+//	struct: => the exported fields, whose keys are the names or the tags, see TagName
+//
+// And the pointer to the map and struct.
+//
+// If an item of the string has no "=", return a *ConversionError
+// with ErrSyntax, whose Path is the index of the item, such as "[1]".
+// If failing to convert a key or value, the returned *ConversionError
+// reports the key by the field Path, such as "[key]".
+func ToMapWith[K comparable, V any](c *Converter, src interface{}) (dst map[K]V, err error) {
+	err = c.setMap(reflect.ValueOf(&dst).Elem(), src)
+	return
+}
+
+// ToStringMap is equal to DefaultConverter.ToStringMap(src).
+func ToStringMap(src interface{}) (map[string]interface{}, error) {
+	return DefaultConverter.ToStringMap(src)
+}
+
+// ToStringMapString is equal to DefaultConverter.ToStringMapString(src).
+func ToStringMapString(src interface{}) (map[string]string, error) {
+	return DefaultConverter.ToStringMapString(src)
+}
+
+// ToStringMapInt64 is equal to DefaultConverter.ToStringMapInt64(src).
+func ToStringMapInt64(src interface{}) (map[string]int64, error) {
+	return DefaultConverter.ToStringMapInt64(src)
+}
+
+// ToStringMapBool is equal to DefaultConverter.ToStringMapBool(src).
+func ToStringMapBool(src interface{}) (map[string]bool, error) {
+	return DefaultConverter.ToStringMapBool(src)
+}
+
+// ToStringMap is equal to ToMapWith[string, interface{}](c, src).
+func (c *Converter) ToStringMap(src interface{}) (map[string]interface{}, error) {
+	return ToMapWith[string, interface{}](c, src)
+}
+
+// ToStringMapString is equal to ToMapWith[string, string](c, src).
+func (c *Converter) ToStringMapString(src interface{}) (map[string]string, error) {
+	return ToMapWith[string, string](c, src)
+}
+
+// ToStringMapInt64 is equal to ToMapWith[string, int64](c, src).
+func (c *Converter) ToStringMapInt64(src interface{}) (map[string]int64, error) {
+	return ToMapWith[string, int64](c, src)
+}
+
+// ToStringMapBool is equal to ToMapWith[string, bool](c, src).
+func (c *Converter) ToStringMapBool(src interface{}) (map[string]bool, error) {
+	return ToMapWith[string, bool](c, src)
+}
+
+// setMap converts src to a new map and sets it to dst,
+// which must be a settable map value. dst is not changed on failure.
+func (c *Converter) setMap(dst reflect.Value, src interface{}) (err error) {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	var keys, values []interface{}
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Pointer:
+		dst.SetZero()
+		return
+
+	case reflect.String:
+		if keys, values, err = c.splitPairs(v.String(), dst.Type()); err != nil {
+			return
+		}
+
+	case reflect.Slice:
+		b, ok := src.([]byte)
+		if !ok {
+			return newError("Set", src, dst.Type(), ErrUnsupported, nil)
+		}
+		if keys, values, err = c.splitPairs(string(b), dst.Type()); err != nil {
+			return
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			dst.SetZero()
+			return
+		}
+
+		keys = make([]interface{}, 0, v.Len())
+		values = make([]interface{}, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			keys = append(keys, iter.Key().Interface())
+			values = append(values, iter.Value().Interface())
+		}
+
+	case reflect.Struct:
+		fields, err := getStructFields(v.Type())
+		if err != nil {
+			return newError("Set", src, dst.Type(), nil, err)
+		}
+
+		for i := range fields {
+			value, ok := fieldByIndex(v, fields[i].Index, false)
+			switch {
+			case !ok, fields[i].omitEmpty && isEmptyValue(value):
+			case fields[i].remain:
+				for iter := value.MapRange(); iter.Next(); {
+					keys = append(keys, iter.Key().Interface())
+					values = append(values, iter.Value().Interface())
+				}
+			default:
+				keys = append(keys, fields[i].key)
+				values = append(values, value.Interface())
+			}
+		}
+
+	default:
+		return newError("Set", src, dst.Type(), ErrUnsupported, nil)
+	}
+
+	if keys == nil {
+		dst.SetZero()
+		return
+	}
+
+	ktype, vtype := dst.Type().Key(), dst.Type().Elem()
+	m := reflect.MakeMapWithSize(dst.Type(), len(keys))
+	for i := range keys {
+		path := fmt.Sprintf("[%v]", keys[i])
+
+		key := reflect.New(ktype).Elem()
+		if err = c.setValue(key, keys[i]); err != nil {
+			return prefixPath(wrapSetError(keys[i], ktype, err), path)
+		}
+
+		value := reflect.New(vtype).Elem()
+		if err = c.enter(path).setValue(value, values[i]); err != nil {
+			return prefixPath(wrapSetError(values[i], vtype, err), path)
+		}
+
+		m.SetMapIndex(key, value)
+	}
+
+	dst.Set(m)
+	return
+}
+
+// splitPairs splits the string like "k1=v1,k2=v2" into the keys and values
+// to be converted to the map type typ.
+//
+// If an item has no "=", return a *ConversionError with ErrSyntax,
+// whose path is the index of the item, such as "[1]".
+func (c *Converter) splitPairs(s string, typ reflect.Type) (keys, values []interface{}, err error) {
+	items := c.splitString(s)
+	if len(items) == 0 {
+		return
+	}
+
+	keys = make([]interface{}, len(items))
+	values = make([]interface{}, len(items))
+	for i, item := range items {
+		key, value, ok := strings.Cut(item.(string), "=")
+		if !ok {
+			err = newError("Set", item, typ, ErrSyntax, nil)
+			return nil, nil, prefixPath(err, "["+strconv.Itoa(i)+"]")
+		}
+		keys[i], values[i] = strings.TrimSpace(key), strings.TrimSpace(value)
+	}
+	return
+}
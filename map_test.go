@@ -0,0 +1,104 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleToMap() {
+	fmt.Println(ToMap[string, int64](map[string]interface{}{"a": "1", "b": 2.0}))
+	fmt.Println(ToMap[string, time.Duration](map[interface{}]interface{}{"a": "1s", "b": 2000}))
+	fmt.Println(ToMap[int, bool]("1=true, 2=false"))
+	fmt.Println(ToMap[string, string](struct {
+		Name string
+		Age  int
+		addr string
+	}{Name: "Aaron", Age: 18}))
+
+	_, err := ToMap[string, int]("a=1,b=x")
+	fmt.Println(err)
+
+	// Output:
+	// map[a:1 b:2] <nil>
+	// map[a:1s b:2s] <nil>
+	// map[1:true 2:false] <nil>
+	// map[Age:18 Name:Aaron] <nil>
+	// cast.ToInt64: [b]: cannot convert string("x") to int64: strconv.ParseInt: parsing "x": invalid syntax
+}
+
+func TestToMap(t *testing.T) {
+	if v, err := ToStringMap(map[interface{}]interface{}{"a": 1, 2: "b", "c": nil}); err != nil {
+		t.Error(err)
+	} else if expect := map[string]interface{}{"a": 1, "2": "b", "c": nil}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToStringMapString(&map[string]int{"a": 1}); err != nil {
+		t.Error(err)
+	} else if expect := map[string]string{"a": "1"}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToStringMapInt64([]byte("a=1;b=2")); err == nil {
+		t.Errorf("expect an error, but got %v", v)
+	}
+
+	c := NewConverter(WithSeparator(";"))
+	if v, err := c.ToStringMapInt64([]byte("a=1;b=2")); err != nil {
+		t.Error(err)
+	} else if expect := map[string]int64{"a": 1, "b": 2}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToStringMapBool(map[string]string{"a": "true", "b": ""}); err != nil {
+		t.Error(err)
+	} else if expect := map[string]bool{"a": true, "b": false}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	for _, src := range []interface{}{nil, "", (*map[string]int)(nil), map[string]int(nil)} {
+		if v, err := ToStringMapInt64(src); err != nil {
+			t.Error(err)
+		} else if v != nil {
+			t.Errorf("expect nil, but got %v", v)
+		}
+	}
+
+	_, err := ToMap[int, int](map[string]int{"x": 1})
+	var ce *ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if ce.Path != "[x]" {
+		t.Errorf("expect path '[x]', but got '%s'", ce.Path)
+	}
+
+	_, err = ToStringMap("a=1,b")
+	if !errors.As(err, &ce) {
+		t.Fatalf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if !errors.Is(err, ErrSyntax) {
+		t.Errorf("expect kind '%v', but got '%v'", ErrSyntax, ce.Kind)
+	} else if ce.Path != "[1]" || ce.Value != "b" {
+		t.Errorf("expect the item 'b' at path '[1]', but got '%v' at '%s'", ce.Value, ce.Path)
+	}
+
+	if _, err = ToStringMap(123); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect error '%v', but got '%v'", ErrUnsupported, err)
+	}
+}
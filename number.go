@@ -0,0 +1,481 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// WithLossless returns an option to set whether the converter
+// rejects the conversions losing the information.
+func WithLossless(lossless bool) Option {
+	return func(c *Converter) { c.Lossless = lossless }
+}
+
+func precisionLossError(src interface{}, typ reflect.Type) error {
+	return newError(opOf(typ), src, typ, ErrPrecisionLoss, nil)
+}
+
+// OverflowPolicy is the policy to handle the value overflowing the target type.
+type OverflowPolicy uint8
+
+const (
+	// OverflowReject returns a *ConversionError with the kind ErrOverflow
+	// if the value overflows, or ErrNegative if converting a negative
+	// to an unsigned integer.
+	OverflowReject OverflowPolicy = iota
+
+	// OverflowSaturate clamps the overflowed value to the minimum
+	// or maximum value of the target type, such as MaxInt32 or MinInt32.
+	// A negative value converted to an unsigned integer is clamped to 0,
+	// and NaN converted to an integer is 0.
+	OverflowSaturate
+
+	// OverflowWrap wraps the overflowed value around like the conversion
+	// between the integer types in Go, that's, keeps only the low bits.
+	OverflowWrap
+)
+
+// WithOverflow returns an option to set the overflow policy of the converter.
+func WithOverflow(policy OverflowPolicy) Option {
+	return func(c *Converter) { c.Overflow = policy }
+}
+
+// RoundingMode is the mode to round a float to an integer.
+type RoundingMode uint8
+
+const (
+	// RoundTruncate rounds toward zero, that's, drops the fractional part.
+	RoundTruncate RoundingMode = iota
+
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+
+	// RoundCeil rounds toward positive infinity.
+	RoundCeil
+
+	// RoundHalfUp rounds to the nearest integer, and rounds half away from zero.
+	RoundHalfUp
+
+	// RoundHalfEven rounds to the nearest integer, and rounds half to even.
+	RoundHalfEven
+)
+
+// WithRounding returns an option to set the rounding mode of the converter.
+func WithRounding(mode RoundingMode) Option {
+	return func(c *Converter) { c.Rounding = mode }
+}
+
+// Round rounds the float v to an integer by the rounding mode.
+func (m RoundingMode) Round(v float64) float64 {
+	switch m {
+	case RoundFloor:
+		return math.Floor(v)
+	case RoundCeil:
+		return math.Ceil(v)
+	case RoundHalfUp:
+		return math.Round(v)
+	case RoundHalfEven:
+		return math.RoundToEven(v)
+	default:
+		return math.Trunc(v)
+	}
+}
+
+// roundFraction rounds q+f by the rounding mode, where q is the integral
+// part and f is the fractional part in (-1, 1) with the same sign as q.
+//
+// Unlike Round, it does not lose the precision when q is beyond 2^53.
+func (m RoundingMode) roundFraction(q int64, f float64) int64 {
+	if m == RoundHalfEven {
+		p := q & 1 // Keep the parity of q to round half to even.
+		return q - p + int64(math.RoundToEven(float64(p)+f))
+	}
+	return q + int64(m.Round(f))
+}
+
+// ToInt is equal to DefaultConverter.ToInt(any).
+func ToInt(any interface{}) (int, error) { return DefaultConverter.ToInt(any) }
+
+// ToInt8 is equal to DefaultConverter.ToInt8(any).
+func ToInt8(any interface{}) (int8, error) { return DefaultConverter.ToInt8(any) }
+
+// ToInt16 is equal to DefaultConverter.ToInt16(any).
+func ToInt16(any interface{}) (int16, error) { return DefaultConverter.ToInt16(any) }
+
+// ToInt32 is equal to DefaultConverter.ToInt32(any).
+func ToInt32(any interface{}) (int32, error) { return DefaultConverter.ToInt32(any) }
+
+// ToUint is equal to DefaultConverter.ToUint(any).
+func ToUint(any interface{}) (uint, error) { return DefaultConverter.ToUint(any) }
+
+// ToUint8 is equal to DefaultConverter.ToUint8(any).
+func ToUint8(any interface{}) (uint8, error) { return DefaultConverter.ToUint8(any) }
+
+// ToUint16 is equal to DefaultConverter.ToUint16(any).
+func ToUint16(any interface{}) (uint16, error) { return DefaultConverter.ToUint16(any) }
+
+// ToUint32 is equal to DefaultConverter.ToUint32(any).
+func ToUint32(any interface{}) (uint32, error) { return DefaultConverter.ToUint32(any) }
+
+// ToFloat32 is equal to DefaultConverter.ToFloat32(any).
+func ToFloat32(any interface{}) (float32, error) { return DefaultConverter.ToFloat32(any) }
+
+// ToInt uses ToInt64 to convert any to a int value,
+// and handles the result overflowing int by the overflow policy.
+func (c *Converter) ToInt(any interface{}) (int, error) {
+	v, err := c.toIntN(any, intType)
+	return int(v), err
+}
+
+// ToInt8 uses ToInt64 to convert any to a int8 value,
+// and handles the result overflowing int8 by the overflow policy.
+func (c *Converter) ToInt8(any interface{}) (int8, error) {
+	v, err := c.toIntN(any, int8Type)
+	return int8(v), err
+}
+
+// ToInt16 uses ToInt64 to convert any to a int16 value,
+// and handles the result overflowing int16 by the overflow policy.
+func (c *Converter) ToInt16(any interface{}) (int16, error) {
+	v, err := c.toIntN(any, int16Type)
+	return int16(v), err
+}
+
+// ToInt32 uses ToInt64 to convert any to a int32 value,
+// and handles the result overflowing int32 by the overflow policy.
+func (c *Converter) ToInt32(any interface{}) (int32, error) {
+	v, err := c.toIntN(any, int32Type)
+	return int32(v), err
+}
+
+// ToUint uses ToUint64 to convert any to a uint value,
+// and handles the result overflowing uint by the overflow policy.
+func (c *Converter) ToUint(any interface{}) (uint, error) {
+	v, err := c.toUintN(any, uintType)
+	return uint(v), err
+}
+
+// ToUint8 uses ToUint64 to convert any to a uint8 value,
+// and handles the result overflowing uint8 by the overflow policy.
+func (c *Converter) ToUint8(any interface{}) (uint8, error) {
+	v, err := c.toUintN(any, uint8Type)
+	return uint8(v), err
+}
+
+// ToUint16 uses ToUint64 to convert any to a uint16 value,
+// and handles the result overflowing uint16 by the overflow policy.
+func (c *Converter) ToUint16(any interface{}) (uint16, error) {
+	v, err := c.toUintN(any, uint16Type)
+	return uint16(v), err
+}
+
+// ToUint32 uses ToUint64 to convert any to a uint32 value,
+// and handles the result overflowing uint32 by the overflow policy.
+func (c *Converter) ToUint32(any interface{}) (uint32, error) {
+	v, err := c.toUintN(any, uint32Type)
+	return uint32(v), err
+}
+
+// ToFloat32 uses ToFloat64 to convert any to a float32 value,
+// and handles the result overflowing float32 by the overflow policy.
+func (c *Converter) ToFloat32(any interface{}) (float32, error) {
+	v, err := c.toFloatN(any, float32Type)
+	return float32(v), err
+}
+
+func (c *Converter) toIntN(src interface{}, typ reflect.Type) (v int64, err error) {
+	if v, err = c.ToInt64(src); err == nil {
+		v, err = c.narrowInt(src, v, typ)
+	}
+	return
+}
+
+func (c *Converter) toUintN(src interface{}, typ reflect.Type) (v uint64, err error) {
+	if v, err = c.ToUint64(src); err == nil {
+		v, err = c.narrowUint(src, v, typ)
+	}
+	return
+}
+
+func (c *Converter) toFloatN(src interface{}, typ reflect.Type) (v float64, err error) {
+	if v, err = c.ToFloat64(src); err == nil {
+		v, err = c.narrowFloat(src, v, typ)
+	}
+	return
+}
+
+// narrowInt checks whether the int64 value v, converted from src,
+// overflows the signed integer type typ, and handles the overflowed
+// value by the overflow policy.
+func (c *Converter) narrowInt(src interface{}, v int64, typ reflect.Type) (int64, error) {
+	if bitsize := typ.Bits(); bitsize < 64 {
+		min := int64(-1) << (bitsize - 1)
+		if max := -(min + 1); v < min || v > max {
+			shift := 64 - bitsize
+			return c.overflowInt(src, typ, v > max, min, max, v<<shift>>shift)
+		}
+	}
+	return v, nil
+}
+
+// narrowUint checks whether the uint64 value v, converted from src,
+// overflows the unsigned integer type typ, and handles the overflowed
+// value by the overflow policy.
+func (c *Converter) narrowUint(src interface{}, v uint64, typ reflect.Type) (uint64, error) {
+	if bitsize := typ.Bits(); bitsize < 64 && v > 1<<bitsize-1 {
+		max := uint64(1)<<bitsize - 1
+		return c.overflowUint(src, typ, true, max, v&max)
+	}
+	return v, nil
+}
+
+// narrowFloat checks whether the float64 value v, converted from src,
+// overflows the float type typ, and handles the overflowed value
+// by the overflow policy.
+//
+// Notice: NaN and ±Inf are not considered as the overflow.
+func (c *Converter) narrowFloat(src interface{}, v float64, typ reflect.Type) (float64, error) {
+	if typ.Bits() != 32 {
+		return v, nil
+	}
+
+	if !math.IsInf(v, 0) && math.Abs(v) > math.MaxFloat32 {
+		switch c.Overflow {
+		case OverflowSaturate:
+			return math.Copysign(math.MaxFloat32, v), nil
+		case OverflowWrap:
+			return float64(float32(v)), nil
+		default:
+			return 0, newError(opOf(typ), src, typ, ErrOverflow, nil)
+		}
+	}
+
+	if c.Lossless && float64(float32(v)) != v && !math.IsNaN(v) {
+		return 0, precisionLossError(src, typ)
+	}
+	return v, nil
+}
+
+// overflowInt handles the overflowed signed integer by the overflow policy,
+// which returns max or min for OverflowSaturate, and wrapped for OverflowWrap.
+func (c *Converter) overflowInt(src interface{}, typ reflect.Type, positive bool, min, max, wrapped int64) (int64, error) {
+	switch c.Overflow {
+	case OverflowSaturate:
+		if positive {
+			return max, nil
+		}
+		return min, nil
+
+	case OverflowWrap:
+		return wrapped, nil
+
+	default:
+		return 0, newError(opOf(typ), src, typ, ErrOverflow, nil)
+	}
+}
+
+// overflowUint handles the overflowed unsigned integer by the overflow policy,
+// which returns max or 0 for OverflowSaturate, and wrapped for OverflowWrap.
+func (c *Converter) overflowUint(src interface{}, typ reflect.Type, positive bool, max, wrapped uint64) (uint64, error) {
+	switch c.Overflow {
+	case OverflowSaturate:
+		if positive {
+			return max, nil
+		}
+		return 0, nil
+
+	case OverflowWrap:
+		return wrapped, nil
+
+	default:
+		if positive {
+			return 0, newError(opOf(typ), src, typ, ErrOverflow, nil)
+		}
+		return 0, newError(opOf(typ), src, typ, ErrNegative, nil)
+	}
+}
+
+// uintToInt64 converts the uint64 value v, converted from src, to int64.
+func (c *Converter) uintToInt64(src interface{}, v uint64) (int64, error) {
+	if v > math.MaxInt64 {
+		return c.overflowInt(src, int64Type, true, math.MinInt64, math.MaxInt64, int64(v))
+	}
+	return int64(v), nil
+}
+
+// intToUint64 converts the int64 value v, converted from src, to uint64.
+func (c *Converter) intToUint64(src interface{}, v int64) (uint64, error) {
+	if v < 0 {
+		return c.overflowUint(src, uint64Type, false, math.MaxUint64, uint64(v))
+	}
+	return uint64(v), nil
+}
+
+// floatToInt64 rounds the float64 value v, converted from src,
+// by the rounding mode, and converts it to int64.
+func (c *Converter) floatToInt64(src interface{}, v float64, typ reflect.Type) (int64, error) {
+	if c.Lossless && v != math.Trunc(v) && !math.IsInf(v, 0) && !math.IsNaN(v) {
+		return 0, precisionLossError(src, typ)
+	}
+
+	switch v = c.Rounding.Round(v); {
+	case math.IsNaN(v):
+		return c.overflowInt(src, typ, false, 0, 0, 0)
+	case v >= 1<<63, v < -1<<63:
+		return c.overflowInt(src, typ, v > 0, math.MinInt64, math.MaxInt64, wrapFloat(v))
+	default:
+		return int64(v), nil
+	}
+}
+
+// floatToUint64 rounds the float64 value v, converted from src,
+// by the rounding mode, and converts it to uint64.
+func (c *Converter) floatToUint64(src interface{}, v float64) (uint64, error) {
+	if c.Lossless && v != math.Trunc(v) && !math.IsInf(v, 0) && !math.IsNaN(v) {
+		return 0, precisionLossError(src, uint64Type)
+	}
+
+	switch v = c.Rounding.Round(v); {
+	case math.IsNaN(v):
+		if c.Overflow == OverflowReject {
+			return 0, newError("ToUint64", src, uint64Type, ErrOverflow, nil)
+		}
+		return 0, nil
+	case v >= 1<<64:
+		return c.overflowUint(src, uint64Type, true, math.MaxUint64, uint64(wrapFloat(v)))
+	case v < 0:
+		return c.overflowUint(src, uint64Type, false, math.MaxUint64, uint64(wrapFloat(v)))
+	default:
+		return uint64(v), nil
+	}
+}
+
+// maxExactFloat64 is the maximum integer from which not all the integers
+// can be represented exactly by float64.
+const maxExactFloat64 = 1 << 53
+
+// intToFloat64 converts the int64 value v, converted from src, to float64.
+func (c *Converter) intToFloat64(src interface{}, v int64) (float64, error) {
+	if c.Lossless && (v > maxExactFloat64 || v < -maxExactFloat64) {
+		return 0, precisionLossError(src, float64Type)
+	}
+	return float64(v), nil
+}
+
+// uintToFloat64 converts the uint64 value v, converted from src, to float64.
+func (c *Converter) uintToFloat64(src interface{}, v uint64) (float64, error) {
+	if c.Lossless && v > maxExactFloat64 {
+		return 0, precisionLossError(src, float64Type)
+	}
+	return float64(v), nil
+}
+
+// durationToInt converts the duration d, converted from src,
+// to an integer in the duration unit.
+func (c *Converter) durationToInt(src interface{}, d time.Duration) (int64, error) {
+	unit := c.intDurationUnit()
+	if c.Lossless && d%unit != 0 {
+		return 0, precisionLossError(src, int64Type)
+	}
+	return int64(d / unit), nil
+}
+
+// durationToFloat converts the duration d to a float in the duration unit.
+func (c *Converter) durationToFloat(d time.Duration) float64 {
+	return float64(d) / float64(c.floatDurationUnit())
+}
+
+// wrapIntString returns the low 64 bits of the integer string s in base
+// multiplied by mul, which is the wrapped value of the overflowed integer.
+func wrapIntString(s string, base int, mul int64) uint64 {
+	v, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return 0
+	}
+
+	v.Mul(v, big.NewInt(mul))
+	return v.And(v, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+}
+
+// wrapFloat truncates the float to an integer and keeps its low 64 bits.
+func wrapFloat(v float64) int64 {
+	if math.IsInf(v, 0) {
+		return 0
+	}
+
+	v = math.Mod(math.Trunc(v), 1<<64) // v is integral and in (-2^64, 2^64).
+	if v < 0 {
+		return int64(-uint64(-v))
+	}
+	return int64(uint64(v))
+}
+
+// WithIntBase returns an option to set the base to parse a string to an integer.
+func WithIntBase(base int) Option {
+	return func(c *Converter) { c.IntBase = base }
+}
+
+// WithDurationUnit returns an option to set the unit of the number
+// converted to or from time.Duration.
+func WithDurationUnit(unit time.Duration) Option {
+	return func(c *Converter) { c.DurationUnit = unit }
+}
+
+func (c *Converter) intDurationUnit() time.Duration {
+	if c.DurationUnit > 0 {
+		return c.DurationUnit
+	}
+	return time.Millisecond
+}
+
+func (c *Converter) floatDurationUnit() time.Duration {
+	if c.DurationUnit > 0 {
+		return c.DurationUnit
+	}
+	return time.Second
+}
+
+// intToDuration converts the integer in the duration unit, converted from src,
+// to time.Duration.
+func (c *Converter) intToDuration(src interface{}, v int64) (time.Duration, error) {
+	unit := int64(c.intDurationUnit())
+	if min, max := math.MinInt64/unit, math.MaxInt64/unit; v < min || v > max {
+		v, err := c.overflowInt(src, durationType, v > 0, math.MinInt64, math.MaxInt64, v*unit)
+		return time.Duration(v), err
+	}
+	return time.Duration(v * unit), nil
+}
+
+// uintToDuration converts the unsigned integer in the duration unit,
+// converted from src, to time.Duration.
+func (c *Converter) uintToDuration(src interface{}, v uint64) (time.Duration, error) {
+	if v > math.MaxInt64 {
+		unit := int64(c.intDurationUnit())
+		v, err := c.overflowInt(src, durationType, true, math.MinInt64, math.MaxInt64, int64(v)*unit)
+		return time.Duration(v), err
+	}
+	return c.intToDuration(src, int64(v))
+}
+
+// floatToDuration converts the float in the duration unit, converted from src,
+// to time.Duration, which rounds the nanoseconds by the rounding mode.
+func (c *Converter) floatToDuration(src interface{}, v float64) (time.Duration, error) {
+	ns, err := c.floatToInt64(src, v*float64(c.floatDurationUnit()), durationType)
+	return time.Duration(ns), err
+}
@@ -0,0 +1,420 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func ExampleToInt8() {
+	fmt.Println(ToInt8(127))
+	fmt.Println(ToInt8("-128"))
+	fmt.Println(ToInt8(300))
+	fmt.Println(ToUint8("255"))
+	fmt.Println(ToUint8(256))
+	fmt.Println(ToFloat32(math.MaxFloat64))
+
+	// Output:
+	// 127 <nil>
+	// -128 <nil>
+	// 0 cast.ToInt8: cannot convert int(300) to int8: value out of range
+	// 255 <nil>
+	// 0 cast.ToUint8: cannot convert int(256) to uint8: value out of range
+	// 0 cast.ToFloat32: cannot convert float64(1.7976931348623157e+308) to float32: value out of range
+}
+
+func TestNarrow(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     func(interface{}) (interface{}, error)
+		src    interface{}
+		expect interface{}
+		ovf    bool
+	}{
+		{"int16", func(v interface{}) (interface{}, error) { return ToInt16(v) }, 32767, int16(32767), false},
+		{"int16", func(v interface{}) (interface{}, error) { return ToInt16(v) }, -32769, nil, true},
+		{"int32", func(v interface{}) (interface{}, error) { return ToInt32(v) }, "-2147483648", int32(math.MinInt32), false},
+		{"int32", func(v interface{}) (interface{}, error) { return ToInt32(v) }, int64(math.MaxInt32 + 1), nil, true},
+		{"uint16", func(v interface{}) (interface{}, error) { return ToUint16(v) }, 65535, uint16(65535), false},
+		{"uint16", func(v interface{}) (interface{}, error) { return ToUint16(v) }, 65536, nil, true},
+		{"uint32", func(v interface{}) (interface{}, error) { return ToUint32(v) }, uint64(math.MaxUint32), uint32(math.MaxUint32), false},
+		{"uint32", func(v interface{}) (interface{}, error) { return ToUint32(v) }, uint64(math.MaxUint32 + 1), nil, true},
+		{"float32", func(v interface{}) (interface{}, error) { return ToFloat32(v) }, math.Inf(1), float32(math.Inf(1)), false},
+		{"float32", func(v interface{}) (interface{}, error) { return ToFloat32(v) }, -math.MaxFloat64, nil, true},
+	}
+
+	for _, test := range tests {
+		v, err := test.fn(test.src)
+		if test.ovf {
+			var ce *ConversionError
+			if !errors.As(err, &ce) || !errors.Is(err, ErrOverflow) {
+				t.Errorf("%s: expect an overflow error, but got %v", test.name, err)
+			} else if ce.Target.String() != test.name || ce.Value != test.src {
+				t.Errorf("%s: unexpected overflow error %+v", test.name, ce)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if v != test.expect {
+			t.Errorf("%s: expect %v, but got %v", test.name, test.expect, v)
+		}
+	}
+}
+
+func TestSetOverflow(t *testing.T) {
+	var i8 int8 = 1
+	if err := Set(&i8, 300); err == nil {
+		t.Error("int8: expect an error, but got nil")
+	} else if i8 != 1 {
+		t.Errorf("int8: expect %d, but got %d", 1, i8)
+	}
+
+	var u16 uint16
+	if err := Set(&u16, "70000"); err == nil {
+		t.Error("uint16: expect an error, but got nil")
+	}
+
+	var f32 float32
+	if err := Set(&f32, 1e300); err == nil {
+		t.Error("float32: expect an error, but got nil")
+	}
+
+	var mi myInt
+	if err := Set(reflect.ValueOf(&mi), 40000); err == nil {
+		t.Error("myInt: expect an error, but got nil")
+	} else if ce := new(ConversionError); !errors.As(err, &ce) || !errors.Is(err, ErrOverflow) {
+		t.Errorf("myInt: expect an overflow error, but got %v", err)
+	} else if ce.Op != "Set" || ce.Target != reflect.TypeOf(mi) {
+		t.Errorf("myInt: unexpected overflow error %+v", ce)
+	}
+
+	if err := Set(&mi, int16(-1)); err != nil {
+		t.Error(err)
+	} else if mi != -1 {
+		t.Errorf("myInt: expect %d, but got %d", -1, mi)
+	}
+}
+
+func ExampleWithOverflow() {
+	saturate := DefaultConverter.With(WithOverflow(OverflowSaturate))
+	fmt.Println(saturate.ToInt32(int64(math.MaxInt32) + 1))
+	fmt.Println(saturate.ToInt32(int64(math.MinInt32) - 1))
+	fmt.Println(saturate.ToUint64(-1))
+	fmt.Println(saturate.ToInt64(1e20))
+
+	wrap := DefaultConverter.With(WithOverflow(OverflowWrap))
+	fmt.Println(wrap.ToInt8(300))
+	fmt.Println(wrap.ToUint64(-1))
+	fmt.Println(wrap.ToInt64(uint64(math.MaxUint64)))
+
+	// Output:
+	// 2147483647 <nil>
+	// -2147483648 <nil>
+	// 0 <nil>
+	// 9223372036854775807 <nil>
+	// 44 <nil>
+	// 18446744073709551615 <nil>
+	// -1 <nil>
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	reject := NewConverter()
+	saturate := NewConverter(WithOverflow(OverflowSaturate))
+	wrap := NewConverter(WithOverflow(OverflowWrap))
+
+	if _, err := reject.ToInt64(uint64(math.MaxUint64)); err == nil {
+		t.Error("expect an error for uint64 to int64, but got nil")
+	}
+	if _, err := reject.ToInt64(math.NaN()); err == nil {
+		t.Error("expect an error for NaN to int64, but got nil")
+	}
+	if _, err := reject.ToUint64(1e20); err == nil {
+		t.Error("expect an error for 1e20 to uint64, but got nil")
+	}
+	if _, err := reject.ToUint64(-1.5); err == nil {
+		t.Error("expect an error for -1.5 to uint64, but got nil")
+	}
+	if v, err := reject.ToUint64(-0.5); err != nil || v != 0 {
+		t.Errorf("expect 0 for -0.5 to uint64, but got %v (%v)", v, err)
+	}
+
+	const ms = math.MaxInt64/int64(time.Millisecond) + 1
+	for _, src := range []interface{}{ms, uint64(ms), "9223372036855", 1e10} {
+		if _, err := reject.ToDuration(src); err == nil {
+			t.Errorf("%T(%v): expect an error, but got nil", src, src)
+		}
+
+		if v, err := saturate.ToDuration(src); err != nil {
+			t.Errorf("%T(%v): %v", src, src, err)
+		} else if v != math.MaxInt64 {
+			t.Errorf("%T(%v): expect %d, but got %d", src, src, int64(math.MaxInt64), v)
+		}
+	}
+
+	if v, err := saturate.ToDuration(-ms); err != nil {
+		t.Error(err)
+	} else if v != math.MinInt64 {
+		t.Errorf("expect %d, but got %d", int64(math.MinInt64), v)
+	}
+
+	if v, err := wrap.ToDuration(ms); err != nil {
+		t.Error(err)
+	} else if n := int64(ms); v != time.Duration(n*int64(time.Millisecond)) {
+		t.Errorf("expect %d, but got %d", n*int64(time.Millisecond), v)
+	}
+
+	if v, err := wrap.ToInt64(-1e19); err != nil {
+		t.Error(err)
+	} else if expect := int64(1<<64 - 1e19); v != expect {
+		t.Errorf("expect %d, but got %d", expect, v)
+	}
+
+	var u8 uint8
+	if err := saturate.Set(&u8, 1000); err != nil {
+		t.Error(err)
+	} else if u8 != math.MaxUint8 {
+		t.Errorf("expect %d, but got %d", math.MaxUint8, u8)
+	}
+
+	var f32 float32
+	if err := saturate.Set(&f32, -1e300); err != nil {
+		t.Error(err)
+	} else if f32 != -math.MaxFloat32 {
+		t.Errorf("expect %v, but got %v", -math.MaxFloat32, f32)
+	}
+
+	var mi myInt
+	if err := wrap.Set(reflect.ValueOf(&mi), 65537); err != nil {
+		t.Error(err)
+	} else if mi != 1 {
+		t.Errorf("expect %d, but got %d", 1, mi)
+	}
+}
+
+func TestOverflowIntString(t *testing.T) {
+	type convert func(*Converter, string) (interface{}, error)
+	var (
+		toInt64    convert = func(c *Converter, s string) (interface{}, error) { return c.ToInt64(s) }
+		toUint64   convert = func(c *Converter, s string) (interface{}, error) { return c.ToUint64(s) }
+		toDuration convert = func(c *Converter, s string) (interface{}, error) { return c.ToDuration(s) }
+		toInt8     convert = func(c *Converter, s string) (interface{}, error) { return c.ToInt8(s) }
+	)
+
+	tests := []struct {
+		policy OverflowPolicy
+		fn     convert
+		src    string
+		expect interface{}
+	}{
+		{OverflowSaturate, toInt64, "99999999999999999999", int64(math.MaxInt64)},
+		{OverflowSaturate, toInt64, "-99999999999999999999", int64(math.MinInt64)},
+		{OverflowSaturate, toInt64, "0x10000000000000000", int64(math.MaxInt64)},
+		{OverflowSaturate, toUint64, "99999999999999999999999", uint64(math.MaxUint64)},
+		{OverflowSaturate, toDuration, "99999999999999999999", time.Duration(math.MaxInt64)},
+		{OverflowSaturate, toDuration, "-99999999999999999999", time.Duration(math.MinInt64)},
+		{OverflowSaturate, toInt8, "99999999999999999999", int8(math.MaxInt8)},
+
+		{OverflowWrap, toInt64, "9223372036854775808", int64(math.MinInt64)},
+		{OverflowWrap, toInt64, "-9223372036854775809", int64(math.MaxInt64)},
+		{OverflowWrap, toInt64, "0x1_0000_0000_0000_0001", int64(1)},
+		{OverflowWrap, toUint64, "18446744073709551617", uint64(1)},
+		{OverflowWrap, toDuration, "18446744073709551617", time.Millisecond},
+		{OverflowWrap, toInt8, "18446744073709551873", int8(1)},
+
+		{OverflowReject, toInt64, "99999999999999999999", int64(0)},
+		{OverflowReject, toUint64, "99999999999999999999999", uint64(0)},
+		{OverflowReject, toDuration, "99999999999999999999", time.Duration(0)},
+	}
+
+	for _, test := range tests {
+		c := NewConverter(WithOverflow(test.policy))
+		v, err := test.fn(c, test.src)
+		if test.policy == OverflowReject {
+			if !errors.Is(err, ErrOverflow) {
+				t.Errorf("%s: expect ErrOverflow, but got %v", test.src, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %v", test.src, err)
+		}
+
+		if v != test.expect {
+			t.Errorf("%s: expect %v, but got %v", test.src, test.expect, v)
+		}
+	}
+
+	var i64 int64
+	if err := Set(&i64, "99999999999999999999"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect ErrOverflow, but got %v", err)
+	}
+	if err := NewConverter(WithOverflow(OverflowSaturate)).Set(&i64, "99999999999999999999"); err != nil {
+		t.Error(err)
+	} else if i64 != math.MaxInt64 {
+		t.Errorf("expect %d, but got %d", int64(math.MaxInt64), i64)
+	}
+}
+
+func TestNonDecimalFloatString(t *testing.T) {
+	for _, s := range []string{"NaN", "nan", "Inf", "-Inf", "+Infinity", "0x1p4", "0x1.8p1"} {
+		if _, err := ToInt64(s); !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ToInt64(%q): expect ErrSyntax, but got %v", s, err)
+		}
+		if _, err := ToUint64(s); !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ToUint64(%q): expect ErrSyntax, but got %v", s, err)
+		}
+		if _, err := ToDuration(s); err == nil {
+			t.Errorf("ToDuration(%q): expect an error, but got nil", s)
+		}
+	}
+
+	if v, err := ToInt64("1.5e3"); err != nil {
+		t.Error(err)
+	} else if v != 1500 {
+		t.Errorf("expect %d, but got %d", 1500, v)
+	}
+}
+
+func ExampleWithRounding() {
+	modes := []RoundingMode{RoundTruncate, RoundFloor, RoundCeil, RoundHalfUp, RoundHalfEven}
+	for _, mode := range modes {
+		c := NewConverter(WithRounding(mode))
+		v1, _ := c.ToInt64(2.5)
+		v2, _ := c.ToInt64("-2.5")
+		v3, _ := c.ToTime("0.0000000015")  // 1.5ns
+		v4, _ := c.ToDuration("0.0000015") // 1.5ns
+		fmt.Println(v1, v2, v3.UnixNano(), int64(v4))
+	}
+
+	// Output:
+	// 2 -2 1 1
+	// 2 -3 1 1
+	// 3 -2 2 2
+	// 3 -3 2 2
+	// 2 -2 2 2
+}
+
+func TestRounding(t *testing.T) {
+	c := NewConverter(WithRounding(RoundHalfEven))
+
+	if v, err := c.ToUint64("3.5"); err != nil {
+		t.Error(err)
+	} else if v != 4 {
+		t.Errorf("expect %d, but got %d", 4, v)
+	}
+
+	if v, err := c.ToUint64(-0.4); err != nil {
+		t.Error(err)
+	} else if v != 0 {
+		t.Errorf("expect %d, but got %d", 0, v)
+	}
+
+	if _, err := NewConverter(WithRounding(RoundFloor)).ToUint64(-0.4); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+
+	if v, err := c.ToInt8(float32(126.5)); err != nil {
+		t.Error(err)
+	} else if v != 126 {
+		t.Errorf("expect %d, but got %d", 126, v)
+	}
+
+	if v, err := c.ToDuration(1.0000000005); err != nil {
+		t.Error(err)
+	} else if v != time.Second {
+		t.Errorf("expect %s, but got %s", time.Second, v)
+	}
+
+	type Float float64
+	if v, err := c.ToDuration(Float(1.5)); err != nil {
+		t.Error(err)
+	} else if v != time.Second*3/2 {
+		t.Errorf("expect %s, but got %s", time.Second*3/2, v)
+	}
+
+	if _, err := c.ToInt64("abc"); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+}
+
+func TestLossless(t *testing.T) {
+	c := NewConverter(WithLossless(true))
+
+	lossy := []struct {
+		name string
+		fn   func(interface{}) (interface{}, error)
+		src  interface{}
+	}{
+		{"float->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, 1.5},
+		{"string->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, "1.5"},
+		{"float->uint64", func(v interface{}) (interface{}, error) { return c.ToUint64(v) }, float32(0.5)},
+		{"int64->float64", func(v interface{}) (interface{}, error) { return c.ToFloat64(v) }, int64(1 << 60)},
+		{"uint64->float64", func(v interface{}) (interface{}, error) { return c.ToFloat64(v) }, uint64(1<<53 + 1)},
+		{"float64->float32", func(v interface{}) (interface{}, error) { return c.ToFloat32(v) }, 0.1},
+		{"int->float32", func(v interface{}) (interface{}, error) { return c.ToFloat32(v) }, 1<<24 + 1},
+		{"float->time", func(v interface{}) (interface{}, error) { return c.ToTime(v) }, 1e-10},
+		{"time->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, time.Unix(1234567890, 1)},
+		{"duration->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, time.Microsecond},
+	}
+	for _, test := range lossy {
+		if _, err := test.fn(test.src); !errors.Is(err, ErrPrecisionLoss) {
+			t.Errorf("%s: expect ErrPrecisionLoss, but got %v", test.name, err)
+		}
+	}
+
+	lossless := []struct {
+		name   string
+		fn     func(interface{}) (interface{}, error)
+		src    interface{}
+		expect interface{}
+	}{
+		{"float->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, 2.0, int64(2)},
+		{"int64->float64", func(v interface{}) (interface{}, error) { return c.ToFloat64(v) }, int64(-1 << 53), float64(-1 << 53)},
+		{"float64->float32", func(v interface{}) (interface{}, error) { return c.ToFloat32(v) }, 0.5, float32(0.5)},
+		{"time->int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, time.Unix(1234567890, 0), int64(1234567890)},
+	}
+	for _, test := range lossless {
+		if v, err := test.fn(test.src); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if v != test.expect {
+			t.Errorf("%s: expect %v, but got %v", test.name, test.expect, v)
+		}
+	}
+
+	var f32 float32
+	if err := c.Set(&f32, 0.1); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("expect ErrPrecisionLoss, but got %v", err)
+	}
+
+	// NaN is reported as the overflow, not the precision loss.
+	for _, lossless := range []bool{false, true} {
+		c := NewConverter(WithLossless(lossless))
+		if _, err := c.ToInt64(math.NaN()); !errors.Is(err, ErrOverflow) {
+			t.Errorf("lossless=%v: expect ErrOverflow for ToInt64(NaN), but got %v", lossless, err)
+		}
+		if _, err := c.ToUint64(math.NaN()); !errors.Is(err, ErrOverflow) {
+			t.Errorf("lossless=%v: expect ErrOverflow for ToUint64(NaN), but got %v", lossless, err)
+		}
+	}
+
+	if v, err := ToInt64(1.5); err != nil {
+		t.Error(err)
+	} else if v != 1 {
+		t.Errorf("expect %d, but got %d", 1, v)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithRelativeTime returns an option to set whether to parse
+// the relative time expression by TryParseTime.
+func WithRelativeTime(relative bool) Option {
+	return func(c *Converter) { c.RelativeTime = relative }
+}
+
+// WithNow returns an option to set the function returning the current time,
+// which is the anchor of the relative time expression.
+func WithNow(now func() time.Time) Option {
+	return func(c *Converter) { c.Now = now }
+}
+
+func (c *Converter) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// ParseRelativeTime is equal to DefaultConverter.ParseRelativeTime(value, loc).
+func ParseRelativeTime(value string, loc *time.Location) (time.Time, error) {
+	return DefaultConverter.ParseRelativeTime(value, loc)
+}
+
+// ParseRelativeTime parses the relative time expression, like Grafana,
+// to time.Time in loc, which is relative to the current time returned
+// by c.Now, or time.Now if nil.
+//
+// The expression starts with an anchor, or a sign if the anchor is
+// omitted, which means "now", followed by zero or more operations:
+//
+//	now: => the current time
+//	today: => now/d
+//	yesterday: => now-1d/d
+//	tomorrow: => now+1d/d
+//	+N<unit>, -N<unit>: => add or subtract N units
+//	/<unit>: => round down to the start of the unit
+//
+// The unit is one of "ms", "s", "m", "h", "d", "w" (starting on Monday),
+// "M" (month) and "y". For example, "now-15m", "now+1d/d", "-2h" and
+// "today-1w/w". The days, weeks, months and years are added in the wall
+// clock of loc, which keeps the time of day across the DST transitions.
+//
+// If loc is nil, use c.Location or defaults.TimeLocation instead.
+func (c *Converter) ParseRelativeTime(value string, loc *time.Location) (time.Time, error) {
+	loc = c.location(loc)
+	if t, ok := c.parseRelativeTime(value, loc); ok {
+		return t, nil
+	}
+	return time.Time{}.In(loc), newError("ParseRelativeTime", value, timeType, ErrSyntax, nil)
+}
+
+// relativeAnchors is the anchors of the relative time expression.
+var relativeAnchors = []string{"now", "today", "yesterday", "tomorrow"}
+
+// parseRelativeTime parses the relative time expression value,
+// and returns false if value does not match the grammar.
+//
+// The current time is got only if value matches the grammar,
+// so it costs little for the other values like "2006-01-02".
+func (c *Converter) parseRelativeTime(value string, loc *time.Location) (time.Time, bool) {
+	var anchor string
+	for _, s := range relativeAnchors {
+		if strings.HasPrefix(value, s) {
+			anchor, value = s, value[len(s):]
+			break
+		}
+	}
+	if anchor == "" && (value == "" || (value[0] != '+' && value[0] != '-')) {
+		return time.Time{}, false
+	}
+
+	for ops := value; ops != ""; {
+		var ok bool
+		if _, _, ops, ok = cutRelativeOp(ops); !ok {
+			return time.Time{}, false
+		}
+	}
+
+	var ok bool
+	t := c.now().In(loc)
+	switch anchor {
+	case "today":
+		t = truncateTime(t, "d")
+	case "yesterday":
+		t = truncateTime(t.AddDate(0, 0, -1), "d")
+	case "tomorrow":
+		t = truncateTime(t.AddDate(0, 0, 1), "d")
+	}
+
+	for value != "" {
+		op := value[0]
+		n, unit, rest, _ := cutRelativeOp(value)
+		if op == '/' {
+			t = truncateTime(t, unit)
+		} else if t, ok = addTime(t, n, unit); !ok {
+			return time.Time{}, false
+		}
+		value = rest
+	}
+
+	return t, true
+}
+
+// cutRelativeOp cuts an operation of the relative time expression from
+// the beginning of s, that's, "+N<unit>", "-N<unit>" or "/<unit>",
+// and n is 0 for "/<unit>".
+func cutRelativeOp(s string) (n int64, unit, rest string, ok bool) {
+	switch s[0] {
+	case '+', '-':
+		end := 1
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+
+		var err error
+		if n, err = strconv.ParseInt(s[:end], 10, 64); err != nil {
+			return 0, "", s, false
+		}
+		unit, rest, ok = cutTimeUnit(s[end:])
+
+	case '/':
+		unit, rest, ok = cutTimeUnit(s[1:])
+	}
+	return
+}
+
+// timeUnits is the units of the relative time expression,
+// in which "ms" must be before "m" to be matched first.
+var timeUnits = []string{"ms", "s", "m", "h", "d", "w", "M", "y"}
+
+// cutTimeUnit cuts the time unit from the beginning of s.
+func cutTimeUnit(s string) (unit, rest string, ok bool) {
+	for _, unit := range timeUnits {
+		if strings.HasPrefix(s, unit) {
+			return unit, s[len(unit):], true
+		}
+	}
+	return "", s, false
+}
+
+// addTime adds n units to t, and returns false if n is out of range.
+func addTime(t time.Time, n int64, unit string) (time.Time, bool) {
+	var d time.Duration
+	switch unit {
+	case "ms":
+		d = time.Millisecond
+	case "s":
+		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
+	default: // Limit the calendar units to avoid overflowing int on 32-bit platforms.
+		if n > math.MaxInt32/7 || n < math.MinInt32/7 {
+			return t, false
+		}
+
+		switch unit {
+		case "d":
+			return t.AddDate(0, 0, int(n)), true
+		case "w":
+			return t.AddDate(0, 0, int(n)*7), true
+		case "M":
+			return t.AddDate(0, int(n), 0), true
+		default:
+			return t.AddDate(int(n), 0, 0), true
+		}
+	}
+
+	if n > math.MaxInt64/int64(d) || n < math.MinInt64/int64(d) {
+		return t, false
+	}
+	return t.Add(time.Duration(n) * d), true
+}
+
+// truncateTime rounds t down to the start of the unit in the wall clock.
+func truncateTime(t time.Time, unit string) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	nsec := t.Nanosecond()
+	switch unit {
+	case "ms":
+		nsec -= nsec % int(time.Millisecond)
+	case "s":
+		nsec = 0
+	case "m":
+		sec, nsec = 0, 0
+	case "h":
+		min, sec, nsec = 0, 0, 0
+	case "d":
+		hour, min, sec, nsec = 0, 0, 0, 0
+	case "w":
+		day -= (int(t.Weekday()) + 6) % 7
+		hour, min, sec, nsec = 0, 0, 0, 0
+	case "M":
+		day, hour, min, sec, nsec = 1, 0, 0, 0, 0
+	case "y":
+		month, day, hour, min, sec, nsec = time.January, 1, 0, 0, 0, 0
+	}
+	return time.Date(year, month, day, hour, min, sec, nsec, t.Location())
+}
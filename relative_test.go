@@ -0,0 +1,145 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleParseRelativeTime() {
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	c := NewConverter(WithRelativeTime(true), WithNow(func() time.Time { return now }))
+
+	fmt.Println(c.ToTimeInLocation("now-15m", time.UTC))
+	fmt.Println(c.ToTimeInLocation("now+1d/d", time.UTC))
+	fmt.Println(c.ToTimeInLocation("yesterday", time.UTC))
+	fmt.Println(c.ToTimeInLocation("-2h", time.UTC))
+	fmt.Println(c.ToTimeInLocation("2023-01-02 03:04:05", time.UTC))
+
+	// Output:
+	// 2023-11-14 21:58:20 +0000 UTC <nil>
+	// 2023-11-15 00:00:00 +0000 UTC <nil>
+	// 2023-11-13 00:00:00 +0000 UTC <nil>
+	// 2023-11-14 20:13:20 +0000 UTC <nil>
+	// 2023-01-02 03:04:05 +0000 UTC <nil>
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2024, 2, 29, 13, 14, 15, 123456789, time.UTC) // Thursday
+	c := NewConverter(WithNow(func() time.Time { return now }))
+
+	tests := []struct {
+		value  string
+		expect time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"yesterday-1h", time.Date(2024, 2, 27, 23, 0, 0, 0, time.UTC)},
+		{"now-500ms/s", time.Date(2024, 2, 29, 13, 14, 14, 0, time.UTC)},
+		{"now/ms", time.Date(2024, 2, 29, 13, 14, 15, 123000000, time.UTC)},
+		{"now/m", time.Date(2024, 2, 29, 13, 14, 0, 0, time.UTC)},
+		{"now/h+30m", time.Date(2024, 2, 29, 13, 30, 0, 0, time.UTC)},
+		{"now/w", time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)},
+		{"now-1w/w", time.Date(2024, 2, 19, 0, 0, 0, 0, time.UTC)},
+		{"now/M", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"now+1M/M", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"now-1y/d", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"now/y", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"+90s", now.Add(90 * time.Second)},
+		{"-2h-30m", now.Add(-150 * time.Minute)},
+	}
+
+	for _, test := range tests {
+		if v, err := c.ParseRelativeTime(test.value, time.UTC); err != nil {
+			t.Errorf("%s: %v", test.value, err)
+		} else if !v.Equal(test.expect) {
+			t.Errorf("%s: expect %s, but got %s", test.value, test.expect, v)
+		}
+	}
+
+	for _, value := range []string{"", "now-", "now-1", "now-1x", "now/", "now/x", "nowx", "+", "-h", "1h", "now-9999999999999999999s", "now+1000000000y"} {
+		if _, err := c.ParseRelativeTime(value, time.UTC); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%s: expect ErrSyntax, but got %v", value, err)
+		}
+	}
+}
+
+func TestRelativeTimeLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 2023-03-12 is the day of the DST transition in New York.
+	now := time.Date(2023, 3, 12, 12, 0, 0, 0, loc)
+	c := NewConverter(WithNow(func() time.Time { return now.UTC() }))
+
+	if v, err := c.ParseRelativeTime("today", loc); err != nil {
+		t.Error(err)
+	} else if expect := time.Date(2023, 3, 12, 0, 0, 0, 0, loc); !v.Equal(expect) || v.Location() != loc {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+
+	if v, err := c.ParseRelativeTime("now-1d", loc); err != nil {
+		t.Error(err)
+	} else if expect := time.Date(2023, 3, 11, 12, 0, 0, 0, loc); !v.Equal(expect) {
+		t.Errorf("expect %s, but got %s", expect, v)
+	}
+}
+
+func TestTryParseRelativeTime(t *testing.T) {
+	if _, err := TryParseTime("now", time.UTC); !errors.Is(err, ErrSyntax) {
+		t.Errorf("expect ErrSyntax, but got %v", err)
+	}
+
+	c := NewConverter(WithRelativeTime(true))
+	if v, err := c.ToTime("now-1h"); err != nil {
+		t.Error(err)
+	} else if d := time.Since(v) - time.Hour; d < 0 || d > time.Minute {
+		t.Errorf("unexpected time %s", v)
+	}
+
+	var v time.Time
+	if err := c.Set(&v, "now/d"); err != nil {
+		t.Error(err)
+	} else if h, m, s := v.Clock(); h != 0 || m != 0 || s != 0 {
+		t.Errorf("expect the start of day, but got %s", v)
+	}
+}
+
+func TestRelativeTimeNowCalls(t *testing.T) {
+	var calls int
+	c := NewConverter(WithRelativeTime(true), WithNow(func() time.Time {
+		calls++
+		return time.Now()
+	}))
+
+	for _, value := range []string{"2023-11-14 22:13:20", "2023-11-14", "now-1x", "nowx", "-h", "today/"} {
+		c.TryParseTime(value, time.UTC)
+	}
+	if calls != 0 {
+		t.Errorf("expect no call of Now for the non-relative values, but got %d", calls)
+	}
+
+	if _, err := c.TryParseTime("now-1h", time.UTC); err != nil {
+		t.Error(err)
+	} else if calls != 1 {
+		t.Errorf("expect %d call of Now, but got %d", 1, calls)
+	}
+}
@@ -16,13 +16,21 @@ package cast
 
 import (
 	"database/sql"
-	"fmt"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
 	"reflect"
 	"time"
 )
 
 // Set does the best, using the ToXXX function, to set the value of dst to src.
 //
+// If the converted value overflows the integer or float type of dst,
+// it is handled by the overflow policy of the converter. For the default
+// policy OverflowReject, return a *ConversionError with ErrOverflow
+// and dst is not changed.
+//
 // Support the types as follow:
 //
 //   - *bool
@@ -42,178 +50,248 @@ import (
 //   - *float64
 //   - *time.Time
 //   - *time.Duration
+//   - *struct: => Decode
+//   - *slice: => ToSlice
+//   - *array: => like ToSlice, but the length must be equal
+//   - *map: => ToMap
+//   - **T: => allocate *T if nil, and set the value of T
+//   - *interface: => store src as it is if implementing the interface
 //   - reflect.Value
-//   - interface sql.Scanner
 //   - interface { Set(interface{}) error }
+//   - interface sql.Scanner
+//   - interface encoding.TextUnmarshaler
+//   - interface json.Unmarshaler
+//   - interface encoding.BinaryUnmarshaler
+//   - interface flag.Value
+//
+// The pointers to the builtin types above, such as *time.Time, are handled
+// by the builtin rules even if they implement the interfaces. And dst
+// implementing more than one interface is set by the first one in the order
+// above, for example, Set(interface{}) error is preferred to sql.Scanner.
+//
+// Before the builtin rules above, the hooks registered by RegisterHook
+// are tried first.
 func Set(dst, src interface{}) (err error) {
+	return DefaultConverter.Set(dst, src)
+}
+
+// Set does the best, using the ToXXX methods, to set the value of dst to src.
+//
+// See the package function Set.
+func (c *Converter) Set(dst, src interface{}) (err error) {
+	if len(c.hooks) > 0 {
+		if handled, err := c.runHook(hookTarget(dst), src); handled {
+			return err
+		}
+	}
+
 	switch d := dst.(type) {
 	case nil:
 		return
 
 	case *bool:
 		var v bool
-		if v, err = ToBool(src); err == nil {
+		if v, err = c.ToBool(src); err == nil {
 			*d = v
 		}
 
 	case *string:
 		var v string
-		if v, err = ToString(src); err == nil {
+		if v, err = c.ToString(src); err == nil {
 			*d = v
 		}
 
 	case *float32:
-		var v float64
-		if v, err = ToFloat64(src); err == nil {
-			*d = float32(v)
+		var v float32
+		if v, err = c.ToFloat32(src); err == nil {
+			*d = v
 		}
 
 	case *float64:
 		var v float64
-		if v, err = ToFloat64(src); err == nil {
+		if v, err = c.ToFloat64(src); err == nil {
 			*d = v
 		}
 
 	case *int:
-		var v int64
-		if v, err = ToInt64(src); err == nil {
-			*d = int(v)
+		var v int
+		if v, err = c.ToInt(src); err == nil {
+			*d = v
 		}
 
 	case *int8:
-		var v int64
-		if v, err = ToInt64(src); err == nil {
-			*d = int8(v)
+		var v int8
+		if v, err = c.ToInt8(src); err == nil {
+			*d = v
 		}
 
 	case *int16:
-		var v int64
-		if v, err = ToInt64(src); err == nil {
-			*d = int16(v)
+		var v int16
+		if v, err = c.ToInt16(src); err == nil {
+			*d = v
 		}
 
 	case *int32:
-		var v int64
-		if v, err = ToInt64(src); err == nil {
-			*d = int32(v)
+		var v int32
+		if v, err = c.ToInt32(src); err == nil {
+			*d = v
 		}
 
 	case *int64:
 		var v int64
-		if v, err = ToInt64(src); err == nil {
+		if v, err = c.ToInt64(src); err == nil {
 			*d = v
 		}
 
 	case *uint:
-		var v uint64
-		if v, err = ToUint64(src); err == nil {
-			*d = uint(v)
+		var v uint
+		if v, err = c.ToUint(src); err == nil {
+			*d = v
 		}
 
 	case *uint8:
-		var v uint64
-		if v, err = ToUint64(src); err == nil {
-			*d = uint8(v)
+		var v uint8
+		if v, err = c.ToUint8(src); err == nil {
+			*d = v
 		}
 
 	case *uint16:
-		var v uint64
-		if v, err = ToUint64(src); err == nil {
-			*d = uint16(v)
+		var v uint16
+		if v, err = c.ToUint16(src); err == nil {
+			*d = v
 		}
 
 	case *uint32:
-		var v uint64
-		if v, err = ToUint64(src); err == nil {
-			*d = uint32(v)
+		var v uint32
+		if v, err = c.ToUint32(src); err == nil {
+			*d = v
 		}
 
 	case *uint64:
 		var v uint64
-		if v, err = ToUint64(src); err == nil {
+		if v, err = c.ToUint64(src); err == nil {
 			*d = v
 		}
 
 	case *uintptr:
 		var v uint64
-		if v, err = ToUint64(src); err == nil {
+		if v, err = c.toUintN(src, uintptrType); err == nil {
 			*d = uintptr(v)
 		}
 
 	case *time.Duration:
 		var v time.Duration
-		if v, err = ToDuration(src); err == nil {
+		if v, err = c.ToDuration(src); err == nil {
 			*d = v
 		}
 
 	case *time.Time:
 		var v time.Time
-		if v, err = ToTime(src); err == nil {
+		if v, err = c.ToTime(src); err == nil {
 			*d = v
 		}
 
 	case reflect.Value:
-		err = reflectSet(dst, d, src)
-
-	case interface{ Set(interface{}) error }:
-		err = d.Set(src)
-
-	case sql.Scanner:
-		err = d.Scan(src)
+		err = c.reflectSet(d, src)
 
 	default:
-		err = reflectSet(dst, reflect.ValueOf(dst), src)
+		var handled bool
+		if handled, err = c.setByInterface(dst, reflect.TypeOf(dst), src); !handled {
+			err = c.reflectSet(reflect.ValueOf(dst), src)
+		}
 	}
 
 	return
 }
 
+// hookTarget returns the settable value that dst points to, or the invalid value.
+func hookTarget(dst interface{}) reflect.Value {
+	v, ok := dst.(reflect.Value)
+	if !ok {
+		v = reflect.ValueOf(dst)
+	}
+
+	if !v.CanSet() && v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+var errNotSettable = errors.New("dst cannot be set")
+
+// wrapSetError wraps the error returned by the Set or Scan method of dst
+// as a *ConversionError, and returns it as it is if it is already.
+func wrapSetError(src interface{}, dst reflect.Type, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var ce *ConversionError
+	if errors.As(err, &ce) {
+		return err
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		dst = dst.Elem()
+	}
+	return newError("Set", src, dst, nil, err)
+}
+
 // reflectSet is the same as Set, which does the best to set the reflect value dst to src.
-func reflectSet(orig interface{}, dst reflect.Value, src interface{}) (err error) {
+func (c *Converter) reflectSet(dst reflect.Value, src interface{}) (err error) {
 	if !dst.CanSet() {
-		if dst.Kind() == reflect.Pointer {
-			elem := dst.Elem()
-			if !elem.CanSet() {
-				return fmt.Errorf("the dst value %T cannot be set", dst.Interface())
-			}
-			dst = elem
+		if dst.Kind() != reflect.Pointer || dst.IsNil() || !dst.Elem().CanSet() {
+			return newError("Set", src, dst.Type(), ErrUnsupported, errNotSettable)
+		}
+		dst = dst.Elem()
+	}
+
+	if dst.Type() == timeType {
+		var v time.Time
+		if v, err = c.ToTime(src); err == nil {
+			dst.Set(reflect.ValueOf(v))
 		}
+		return
+	}
+
+	if handled, err := c.setByInterface(dst.Addr().Interface(), dst.Type(), src); handled {
+		return err
 	}
 
 	switch dst.Kind() {
 	case reflect.Bool:
 		var v bool
-		if v, err = ToBool(src); err == nil {
+		if v, err = c.ToBool(src); err == nil {
 			dst.SetBool(v)
 		}
 
 	case reflect.String:
 		var v string
-		if v, err = ToString(src); err == nil {
+		if v, err = c.ToString(src); err == nil {
 			dst.SetString(v)
 		}
 
 	case reflect.Float32, reflect.Float64:
 		var v float64
-		if v, err = ToFloat64(src); err == nil {
+		if v, err = c.toFloatN(src, dst.Type()); err == nil {
 			dst.SetFloat(v)
 		}
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		var v int64
-		if v, err = ToInt64(src); err == nil {
+		if v, err = c.toIntN(src, dst.Type()); err == nil {
 			dst.SetInt(v)
 		}
 
 	case reflect.Int64:
 		if _, ok := dst.Interface().(time.Duration); ok {
-			v, err := ToDuration(src)
+			v, err := c.ToDuration(src)
 			if err != nil {
 				return err
 			}
 			dst.SetInt(int64(v))
 		} else {
-			v, err := ToInt64(src)
+			v, err := c.ToInt64(src)
 			if err != nil {
 				return err
 			}
@@ -222,29 +300,157 @@ func reflectSet(orig interface{}, dst reflect.Value, src interface{}) (err error
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		var v uint64
-		if v, err = ToUint64(src); err == nil {
+		if v, err = c.toUintN(src, dst.Type()); err == nil {
 			dst.SetUint(v)
 		}
 
+	case reflect.Struct:
+		err = c.decodeStruct(dst, src)
+
+	case reflect.Slice:
+		err = c.setSlice(dst, src)
+
+	case reflect.Array:
+		err = c.setArray(dst, src)
+
+	case reflect.Map:
+		err = c.setMap(dst, src)
+
+	case reflect.Pointer:
+		err = c.setPointer(dst, src)
+
+	case reflect.Interface:
+		err = c.setInterface(dst, src)
+
 	default:
-		iface := dst.Addr().Interface()
-		switch d := iface.(type) {
-		case *time.Time:
-			var v time.Time
-			if v, err = ToTime(src); err == nil {
-				dst.Set(reflect.ValueOf(v))
+		err = newError("Set", src, dst.Type(), ErrUnsupported, nil)
+	}
+
+	return
+}
+
+// setByInterface sets dst, whose type is dstType, to src by the method
+// of the interface that dst implements, in the order as follow:
+//
+//  1. interface{ Set(interface{}) error }: => Set(src)
+//  2. sql.Scanner: => Scan(src)
+//  3. encoding.TextUnmarshaler: => UnmarshalText([]byte(ToString(src)))
+//  4. json.Unmarshaler: => UnmarshalJSON(src as JSON)
+//  5. encoding.BinaryUnmarshaler: => UnmarshalBinary(src if []byte, or []byte(ToString(src)))
+//  6. flag.Value: => Set(ToString(src))
+//
+// For json.Unmarshaler, the string or []byte src is passed as it is if it is
+// a valid JSON, or else src is encoded by json.Marshal.
+//
+// If dst implements none of them, return (false, nil).
+func (c *Converter) setByInterface(dst interface{}, dstType reflect.Type, src interface{}) (handled bool, err error) {
+	switch d := dst.(type) {
+	case interface{ Set(interface{}) error }:
+		err = d.Set(src)
+
+	case sql.Scanner:
+		err = d.Scan(src)
+
+	case encoding.TextUnmarshaler:
+		var s string
+		if s, err = c.ToString(src); err == nil {
+			err = d.UnmarshalText([]byte(s))
+		}
+
+	case json.Unmarshaler:
+		var data []byte
+		if data, err = c.toJSON(src); err == nil {
+			err = d.UnmarshalJSON(data)
+		}
+
+	case encoding.BinaryUnmarshaler:
+		data, ok := src.([]byte)
+		if !ok {
+			var s string
+			if s, err = c.ToString(src); err != nil {
+				break
 			}
+			data = []byte(s)
+		}
+		err = d.UnmarshalBinary(data)
 
-		case interface{ Set(interface{}) error }:
-			err = d.Set(src)
+	case flag.Value:
+		var s string
+		if s, err = c.ToString(src); err == nil {
+			err = d.Set(s)
+		}
+
+	default:
+		return false, nil
+	}
+
+	return true, wrapSetError(src, dstType, err)
+}
+
+// toJSON returns the JSON encoding of src. If src is a string or []byte
+// and is a valid JSON, return it as it is.
+func (c *Converter) toJSON(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case json.RawMessage:
+		return v, nil
 
-		case sql.Scanner:
-			err = d.Scan(src)
+	case []byte:
+		if json.Valid(v) {
+			return v, nil
+		}
+		return json.Marshal(string(v))
 
-		default:
-			err = fmt.Errorf("unsupport to set a value to %T(%v)", orig, orig)
+	case string:
+		if data := []byte(v); json.Valid(data) {
+			return data, nil
 		}
 	}
 
-	return
+	return json.Marshal(src)
+}
+
+// setPointer sets the settable pointer value dst to src.
+//
+// If src is nil or a nil pointer, set dst to nil. Or, set the value
+// that dst points to, which is allocated if dst is nil.
+func (c *Converter) setPointer(dst reflect.Value, src interface{}) error {
+	if v := reflect.ValueOf(src); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		dst.SetZero()
+		return nil
+	}
+
+	if !dst.IsNil() {
+		return c.setValue(dst.Elem(), src)
+	}
+
+	v := reflect.New(dst.Type().Elem())
+	if err := c.setValue(v.Elem(), src); err != nil {
+		return err
+	}
+
+	dst.Set(v)
+	return nil
+}
+
+// setInterface sets the settable interface value dst to src.
+//
+// If src is nil, set dst to nil. If src implements the interface,
+// store it as it is. Or, if dst holds a non-nil pointer, set the value
+// that it points to like encoding/json.
+func (c *Converter) setInterface(dst reflect.Value, src interface{}) error {
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+
+	if v := reflect.ValueOf(src); v.Type().Implements(dst.Type()) {
+		dst.Set(v)
+		return nil
+	}
+
+	if e := dst.Elem(); e.Kind() == reflect.Pointer && !e.IsNil() {
+		return c.setValue(e.Elem(), src)
+	}
+
+	return newError("Set", src, dst.Type(), ErrUnsupported, nil)
 }
@@ -17,7 +17,10 @@ package cast
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -360,3 +363,222 @@ func testSetResult(t *testing.T, prefix string, result interface{}, err error, e
 		t.Errorf("%s: expect %v, but got %v", prefix, expect, result)
 	}
 }
+
+func ExampleSet_container() {
+	var ints []int
+	fmt.Println(Set(&ints, []interface{}{"1", 2.0}), ints)
+
+	var bytes [4]byte
+	fmt.Println(Set(&bytes, "abcd"), bytes)
+	fmt.Println(Set(&bytes, []int{1, 2}), bytes)
+
+	var durations map[string]time.Duration
+	fmt.Println(Set(&durations, map[string]interface{}{"a": "1s", "b": 2000}), durations)
+
+	var matrix [][2]uint8
+	fmt.Println(Set(&matrix, [][]string{{"1", "2"}, {"3", "256"}}), matrix)
+
+	// Output:
+	// <nil> [1 2]
+	// <nil> [97 98 99 100]
+	// cast.Set: cannot convert []int([1 2]) to [4]uint8: length mismatch: expect 4 elements, but got 2 [97 98 99 100]
+	// <nil> map[a:1s b:2s]
+	// cast.ToUint8: [1][1]: cannot convert string("256") to uint8: value out of range []
+}
+
+func TestSetContainer(t *testing.T) {
+	var ints []int64
+	testSet(t, &ints, "slice1", "1,2,3", []int64{1, 2, 3})
+	testSet(t, &ints, "slice2", [2]string{"4", "5"}, []int64{4, 5})
+	testSet(t, reflect.ValueOf(&ints), "slice3", 6, []int64{6})
+	testSet(t, &ints, "slice4", nil, []int64(nil))
+
+	var array [3]string
+	testSet(t, &array, "array1", []int{1, 2, 3}, [3]string{"1", "2", "3"})
+	testSet(t, &array, "array2", "a, b, c", [3]string{"a", "b", "c"})
+	testSet(t, &array, "array3", nil, [3]string{})
+
+	var m map[int]bool
+	testSet(t, &m, "map1", "1=true,2=false", map[int]bool{1: true, 2: false})
+	testSet(t, &m, "map2", map[string]int{"3": 1}, map[int]bool{3: true})
+
+	var strs fmtStrings
+	testSet(t, &strs, "setter1", "a,b", fmtStrings{"setter"})
+
+	if err := Set(&array, []int{1}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expect error '%v', but got '%v'", ErrLengthMismatch, err)
+	}
+
+	if v, err := To[[]time.Duration]("1s,2s"); err != nil {
+		t.Error(err)
+	} else if expect := []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+}
+
+type fmtStrings []string
+
+func (s *fmtStrings) Set(src interface{}) error {
+	*s = fmtStrings{"setter"}
+	return nil
+}
+
+func ExampleSet_pointer() {
+	var p *int
+	fmt.Println(Set(&p, "5"), *p)
+
+	var pp **int
+	fmt.Println(Set(&pp, 6.0), **pp)
+
+	fmt.Println(Set(&pp, nil), pp == nil)
+
+	type Optional struct {
+		Name *string
+		Age  *int
+	}
+
+	var opt *Optional
+	fmt.Println(Set(&opt, map[string]interface{}{"Name": "Aaron"}), *opt.Name, opt.Age)
+
+	var iface interface{}
+	fmt.Println(Set(&iface, []int{1, 2}), iface)
+
+	var stringer fmt.Stringer
+	fmt.Println(Set(&stringer, newStringer("abc")), stringer)
+	fmt.Println(Set(&stringer, 123))
+
+	// Output:
+	// <nil> 5
+	// <nil> 6
+	// <nil> true
+	// <nil> Aaron <nil>
+	// <nil> [1 2]
+	// <nil> abc
+	// cast.Set: cannot convert int(123) to fmt.Stringer: unsupported conversion
+}
+
+func TestSetPointer(t *testing.T) {
+	i := 1
+	p := &i
+	testSet(t, &p, "pointer1", "2", &i)
+	if i != 2 {
+		t.Errorf("expect %d, but got %d", 2, i)
+	}
+
+	testSet(t, &p, "pointer2", (*int)(nil), (*int)(nil))
+
+	var pt *time.Time
+	if err := Set(reflect.ValueOf(&pt), 1658555811); err != nil {
+		t.Error(err)
+	} else if pt == nil || pt.Unix() != 1658555811 {
+		t.Errorf("unexpected time %v", pt)
+	}
+
+	// The pointer is not allocated on failure.
+	var pi *int8
+	if err := Set(&pi, 1000); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expect error '%v', but got '%v'", ErrOverflow, err)
+	} else if pi != nil {
+		t.Errorf("expect nil, but got %v", *pi)
+	}
+
+	// Set the value that the interface points to.
+	var s fmtStringer
+	var iface fmt.Stringer = &s
+	testSet(t, &iface, "interface1", 123, fmt.Stringer(&fmtStringer{"123"}))
+	if s.v != "123" {
+		t.Errorf("expect '%s', but got '%s'", "123", s.v)
+	}
+
+	if err := Set(&iface, nil); err != nil {
+		t.Error(err)
+	} else if iface != nil {
+		t.Errorf("expect nil, but got %v", iface)
+	}
+}
+
+type jsonOnly struct{ raw string }
+
+func (j *jsonOnly) UnmarshalJSON(data []byte) error {
+	j.raw = string(data)
+	return nil
+}
+
+type binaryOnly []byte
+
+func (b *binaryOnly) UnmarshalBinary(data []byte) error {
+	*b = append(binaryOnly("bin:"), data...)
+	return nil
+}
+
+type flagValue struct{ values []string }
+
+func (f *flagValue) String() string { return strings.Join(f.values, ",") }
+func (f *flagValue) Set(s string) error {
+	f.values = append(f.values, s)
+	return nil
+}
+
+type textAndSetter struct{ by string }
+
+func (t *textAndSetter) Set(interface{}) error           { t.by = "Set"; return nil }
+func (t *textAndSetter) UnmarshalText(data []byte) error { t.by = "UnmarshalText"; return nil }
+
+func ExampleSet_unmarshaler() {
+	var ip net.IP // encoding.TextUnmarshaler
+	fmt.Println(Set(&ip, "127.0.0.1"), ip)
+
+	var j jsonOnly // json.Unmarshaler
+	fmt.Println(Set(&j, "abc"), j.raw)
+	fmt.Println(Set(&j, `{"a":1}`), j.raw)
+	fmt.Println(Set(&j, 123), j.raw)
+
+	var b binaryOnly // encoding.BinaryUnmarshaler
+	fmt.Println(Set(&b, 123), string(b))
+
+	var f flagValue // flag.Value
+	fmt.Println(Set(&f, 1), Set(&f, true), f.String())
+
+	var ts textAndSetter // Set(interface{}) error is preferred.
+	fmt.Println(Set(&ts, "abc"), ts.by)
+
+	// Output:
+	// <nil> 127.0.0.1
+	// <nil> "abc"
+	// <nil> {"a":1}
+	// <nil> 123
+	// <nil> bin:123
+	// <nil> <nil> 1,true
+	// <nil> Set
+}
+
+func TestSetUnmarshaler(t *testing.T) {
+	type S struct {
+		IP   net.IP
+		Addr netip.Addr
+		JSON jsonOnly
+		Time time.Time // Not by UnmarshalText
+	}
+
+	var s S
+	err := Decode(&s, map[string]interface{}{
+		"IP":   []byte("1.2.3.4"),
+		"Addr": "::1",
+		"JSON": map[string]int{"a": 1},
+		"Time": 1658555811,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.IP.String() != "1.2.3.4" || s.Addr.String() != "::1" || s.JSON.raw != `{"a":1}` || s.Time.Unix() != 1658555811 {
+		t.Errorf("unexpected result %+v", s)
+	}
+
+	var ce *ConversionError
+	if err := Set(reflect.ValueOf(&s.IP), "abc"); !errors.As(err, &ce) {
+		t.Errorf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if ce.Op != "Set" || ce.Target != reflect.TypeOf(s.IP) {
+		t.Errorf("unexpected error %+v", ce)
+	}
+}
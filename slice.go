@@ -0,0 +1,245 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithSeparator returns an option to set the separator
+// to split a string into a slice.
+func WithSeparator(sep string) Option {
+	return func(c *Converter) { c.Separator = sep }
+}
+
+func (c *Converter) separator() string {
+	if c.Separator == "" {
+		return ","
+	}
+	return c.Separator
+}
+
+// ToSlice is equal to ToSliceWith[T](DefaultConverter, src).
+func ToSlice[T any](src interface{}) (dst []T, err error) {
+	return ToSliceWith[T](DefaultConverter, src)
+}
+
+// ToSliceWith converts src to a slice of the type T by the converter c,
+// each element of which is converted by c.Set.
+//
+// src may be one of the types as follow:
+//
+//	nil: => nil
+//	~string: => split by the separator, and trim the spaces of each element
+//	~string: => []byte(src) if T is byte
+//	[]byte: => split like string if it is the printable text, such as []byte("1,2")
+//	slice or array: => convert each element in turn, such as []byte{1, 2}
+//	others: => a slice only containing src
+//
+// If failing to convert an element, the returned *ConversionError
+// reports its index by the field Path, such as "[1]".
+func ToSliceWith[T any](c *Converter, src interface{}) (dst []T, err error) {
+	err = c.setSlice(reflect.ValueOf(&dst).Elem(), src)
+	return
+}
+
+// ToStringSlice is equal to DefaultConverter.ToStringSlice(src).
+func ToStringSlice(src interface{}) ([]string, error) {
+	return DefaultConverter.ToStringSlice(src)
+}
+
+// ToInt64Slice is equal to DefaultConverter.ToInt64Slice(src).
+func ToInt64Slice(src interface{}) ([]int64, error) {
+	return DefaultConverter.ToInt64Slice(src)
+}
+
+// ToFloat64Slice is equal to DefaultConverter.ToFloat64Slice(src).
+func ToFloat64Slice(src interface{}) ([]float64, error) {
+	return DefaultConverter.ToFloat64Slice(src)
+}
+
+// ToBoolSlice is equal to DefaultConverter.ToBoolSlice(src).
+func ToBoolSlice(src interface{}) ([]bool, error) {
+	return DefaultConverter.ToBoolSlice(src)
+}
+
+// ToDurationSlice is equal to DefaultConverter.ToDurationSlice(src).
+func ToDurationSlice(src interface{}) ([]time.Duration, error) {
+	return DefaultConverter.ToDurationSlice(src)
+}
+
+// ToTimeSlice is equal to DefaultConverter.ToTimeSlice(src).
+func ToTimeSlice(src interface{}) ([]time.Time, error) {
+	return DefaultConverter.ToTimeSlice(src)
+}
+
+// ToStringSlice is equal to ToSliceWith[string](c, src).
+func (c *Converter) ToStringSlice(src interface{}) ([]string, error) {
+	return ToSliceWith[string](c, src)
+}
+
+// ToInt64Slice is equal to ToSliceWith[int64](c, src).
+func (c *Converter) ToInt64Slice(src interface{}) ([]int64, error) {
+	return ToSliceWith[int64](c, src)
+}
+
+// ToFloat64Slice is equal to ToSliceWith[float64](c, src).
+func (c *Converter) ToFloat64Slice(src interface{}) ([]float64, error) {
+	return ToSliceWith[float64](c, src)
+}
+
+// ToBoolSlice is equal to ToSliceWith[bool](c, src).
+func (c *Converter) ToBoolSlice(src interface{}) ([]bool, error) {
+	return ToSliceWith[bool](c, src)
+}
+
+// ToDurationSlice is equal to ToSliceWith[time.Duration](c, src).
+func (c *Converter) ToDurationSlice(src interface{}) ([]time.Duration, error) {
+	return ToSliceWith[time.Duration](c, src)
+}
+
+// ToTimeSlice is equal to ToSliceWith[time.Time](c, src).
+func (c *Converter) ToTimeSlice(src interface{}) ([]time.Time, error) {
+	return ToSliceWith[time.Time](c, src)
+}
+
+// setSlice converts src to a new slice and sets it to dst,
+// which must be a settable slice value. dst is not changed on failure.
+func (c *Converter) setSlice(dst reflect.Value, src interface{}) (err error) {
+	if src == nil {
+		dst.SetZero()
+		return
+	}
+
+	items, bytes := c.sliceItems(src, dst.Type().Elem())
+	if bytes != nil {
+		dst.SetBytes(bytes)
+		return
+	}
+
+	slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+	if err = c.setItems(slice, items); err == nil {
+		dst.Set(slice)
+	}
+	return
+}
+
+// setArray converts src to a new array and sets it to dst, which must be
+// a settable array value. dst is not changed on failure.
+//
+// The number of the elements of src must be equal to the length of the array.
+func (c *Converter) setArray(dst reflect.Value, src interface{}) (err error) {
+	if src == nil {
+		dst.SetZero()
+		return
+	}
+
+	items, bytes := c.sliceItems(src, dst.Type().Elem())
+	_len := len(items)
+	if bytes != nil {
+		_len = len(bytes)
+	}
+
+	if _len != dst.Len() {
+		err = fmt.Errorf("%w: expect %d elements, but got %d", ErrLengthMismatch, dst.Len(), _len)
+		return newError("Set", src, dst.Type(), ErrLengthMismatch, err)
+	}
+
+	array := reflect.New(dst.Type()).Elem()
+	if bytes != nil {
+		for i, b := range bytes { // The element may be a named byte type.
+			array.Index(i).SetUint(uint64(b))
+		}
+	} else if err = c.setItems(array, items); err != nil {
+		return
+	}
+
+	dst.Set(array)
+	return
+}
+
+// sliceItems returns the elements of src to be converted to a slice or array
+// whose element type is etype. But if etype is byte and src is a string,
+// return the bytes of the string instead.
+func (c *Converter) sliceItems(src interface{}, etype reflect.Type) (items []interface{}, bytes []byte) {
+	switch v := reflect.ValueOf(src); v.Kind() {
+	case reflect.String:
+		if etype.Kind() == reflect.Uint8 {
+			return nil, []byte(v.String())
+		}
+		items = c.splitString(v.String())
+
+	case reflect.Slice, reflect.Array:
+		if b, ok := src.([]byte); ok && etype.Kind() != reflect.Uint8 && isText(b) {
+			items = c.splitString(string(b))
+			break
+		}
+
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+
+	default:
+		items = []interface{}{src}
+	}
+
+	return
+}
+
+// isText reports whether b is the printable UTF-8 text, which may contain
+// the white spaces, such as []byte("1, 2"), but not []byte{1, 2}.
+func isText(b []byte) bool {
+	for len(b) > 0 {
+		r, n := utf8.DecodeRune(b)
+		if (r == utf8.RuneError && n == 1) || (!unicode.IsPrint(r) && !unicode.IsSpace(r)) {
+			return false
+		}
+		b = b[n:]
+	}
+	return true
+}
+
+// setItems converts and sets the items to the elements of the slice or array
+// value list in turn, which has the same length as items.
+func (c *Converter) setItems(list reflect.Value, items []interface{}) error {
+	etype := list.Type().Elem()
+	for i, item := range items {
+		index := "[" + strconv.Itoa(i) + "]"
+		if err := c.enter(index).setValue(list.Index(i), item); err != nil {
+			return prefixPath(wrapSetError(item, etype, err), index)
+		}
+	}
+	return nil
+}
+
+func (c *Converter) splitString(s string) []interface{} {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+
+	parts := strings.Split(s, c.separator())
+	items := make([]interface{}, len(parts))
+	for i, part := range parts {
+		items[i] = strings.TrimSpace(part)
+	}
+	return items
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleToSlice() {
+	fmt.Println(ToSlice[int]([]interface{}{"1", 2.0, uint8(3)}))
+	fmt.Println(ToSlice[uint16]([3]string{"4", "5", "6"}))
+	fmt.Println(ToSlice[myInt]("7, 8, 9"))
+	fmt.Println(ToSlice[string](123)) // A single scalar
+	fmt.Println(ToSliceWith[bool](NewConverter(WithSeparator(";")), "true;false"))
+
+	_, err := ToSlice[int64]([]string{"1", "abc"})
+	fmt.Println(err)
+
+	// Output:
+	// [1 2 3] <nil>
+	// [4 5 6] <nil>
+	// [7 8 9] <nil>
+	// [123] <nil>
+	// [true false] <nil>
+	// cast.ToInt64: [1]: cannot convert string("abc") to int64: strconv.ParseInt: parsing "abc": invalid syntax
+}
+
+func TestToSlice(t *testing.T) {
+	if v, err := ToStringSlice([]int{1, 2}); err != nil {
+		t.Error(err)
+	} else if expect := []string{"1", "2"}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToInt64Slice([]byte("1,2,3")); err != nil {
+		t.Error(err)
+	} else if expect := []int64{1, 2, 3}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	// The binary bytes are converted element by element.
+	if v, err := ToInt64Slice([]byte{1, 2}); err != nil {
+		t.Error(err)
+	} else if expect := []int64{1, 2}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToInt64Slice([]byte(" 1, 2 \n")); err != nil {
+		t.Error(err)
+	} else if expect := []int64{1, 2}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToSlice[byte]([]byte("ab")); err != nil {
+		t.Error(err)
+	} else if expect := []byte("ab"); !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	type namedByte byte
+	if v, err := ToSlice[namedByte]("ab"); err != nil {
+		t.Error(err)
+	} else if expect := []namedByte{'a', 'b'}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	var array [2]namedByte
+	if err := Set(&array, "ab"); err != nil {
+		t.Error(err)
+	} else if expect := [2]namedByte{'a', 'b'}; array != expect {
+		t.Errorf("expect %v, but got %v", expect, array)
+	}
+
+	if v, err := ToFloat64Slice("1.5,2"); err != nil {
+		t.Error(err)
+	} else if expect := []float64{1.5, 2}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToBoolSlice([]string{"true", "0"}); err != nil {
+		t.Error(err)
+	} else if expect := []bool{true, false}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToDurationSlice("1s,2m"); err != nil {
+		t.Error(err)
+	} else if expect := []time.Duration{time.Second, 2 * time.Minute}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect %v, but got %v", expect, v)
+	}
+
+	if v, err := ToTimeSlice([]int64{0, 1234567890}); err != nil {
+		t.Error(err)
+	} else if len(v) != 2 || v[0].Unix() != 0 || v[1].Unix() != 1234567890 {
+		t.Errorf("unexpected times %v", v)
+	}
+
+	for _, src := range []interface{}{nil, "", " "} {
+		if v, err := ToInt64Slice(src); err != nil {
+			t.Error(err)
+		} else if len(v) != 0 {
+			t.Errorf("expect an empty slice, but got %v", v)
+		}
+	}
+
+	_, err := ToInt64Slice([]interface{}{1, 2, []int{3}})
+	var ce *ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expect a *ConversionError, but got %T(%v)", err, err)
+	} else if ce.Path != "[2]" {
+		t.Errorf("expect path '[2]', but got '%s'", ce.Path)
+	} else if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expect kind '%v', but got '%v'", ErrUnsupported, ce.Kind)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"reflect"
+	"time"
+)
+
+// WithStrict returns an option to set whether the converter
+// only allows the conversions between the values of the same kind.
+func WithStrict(strict bool) Option {
+	return func(c *Converter) { c.Strict = strict }
+}
+
+// valueKind is the kind of a value used by the strict mode.
+type valueKind uint8
+
+const (
+	kindNil valueKind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindTime
+	kindDuration
+	kindOther
+)
+
+func kindOf(src interface{}) valueKind {
+	switch src.(type) {
+	case nil:
+		return kindNil
+	case []byte:
+		return kindString
+	}
+
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return kindNil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return kindBool
+
+	case reflect.String:
+		return kindString
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if v.Type() == durationType {
+			return kindDuration
+		}
+		return kindNumber
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return kindTime
+		}
+	}
+
+	return kindOther
+}
+
+// checkStrict checks whether src is allowed to be converted to the type typ
+// in the strict mode, which only allows nil, the value of the kind expect,
+// or the value implementing one of the interfaces.
+//
+// If not in the strict mode, it always returns nil.
+func (c *Converter) checkStrict(src interface{}, typ reflect.Type, expect valueKind,
+	ifaces ...func(interface{}) bool) error {
+	if !c.Strict {
+		return nil
+	}
+
+	switch kindOf(src) {
+	case kindNil, expect:
+		return nil
+	}
+
+	for _, implements := range ifaces {
+		if implements(src) {
+			return nil
+		}
+	}
+
+	return newError(opOf(typ), src, typ, ErrUnsupported, errStrict)
+}
+
+func isBooler(v interface{}) bool {
+	_, ok := v.(interface{ Bool() bool })
+	return ok
+}
+
+func isInter(v interface{}) bool {
+	switch v.(type) {
+	case interface{ Int64() int64 }, interface{ Int() int64 }:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUinter(v interface{}) bool {
+	switch v.(type) {
+	case interface{ Uint64() uint64 }, interface{ Uint() uint64 }:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloater(v interface{}) bool {
+	switch v.(type) {
+	case interface{ Float64() float64 }, interface{ Float() float64 }:
+		return true
+	default:
+		return false
+	}
+}
+
+func isDurationer(v interface{}) bool {
+	_, ok := v.(interface{ Duration() time.Duration })
+	return ok
+}
+
+func isTimer(v interface{}) bool {
+	_, ok := v.(interface{ Time() time.Time })
+	return ok
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleWithStrict() {
+	c := NewConverter(WithStrict(true))
+	fmt.Println(c.ToBool("1"))
+	fmt.Println(c.ToInt64(true))
+	fmt.Println(c.ToString(123))
+	fmt.Println(c.ToInt8(int64(100)))
+	fmt.Println(c.ToInt8(int64(200)))
+
+	// Output:
+	// false cast.ToBool: cannot convert string("1") to bool: not allowed in the strict mode
+	// 0 cast.ToInt64: cannot convert bool(true) to int64: not allowed in the strict mode
+	//  cast.ToString: cannot convert int(123) to string: not allowed in the strict mode
+	// 100 <nil>
+	// 0 cast.ToInt8: cannot convert int64(200) to int8: value out of range
+}
+
+type strictInt int64
+
+func (i strictInt) Int64() int64 { return int64(i) }
+
+func TestStrict(t *testing.T) {
+	c := NewConverter(WithStrict(true))
+	now := time.Now()
+
+	allowed := []struct {
+		name string
+		fn   func(interface{}) (interface{}, error)
+		src  interface{}
+	}{
+		{"bool", func(v interface{}) (interface{}, error) { return c.ToBool(v) }, true},
+		{"nil", func(v interface{}) (interface{}, error) { return c.ToBool(v) }, nil},
+		{"int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, 1.0},
+		{"int64ptr", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, new(uint8)},
+		{"uint64", func(v interface{}) (interface{}, error) { return c.ToUint64(v) }, int8(1)},
+		{"float64", func(v interface{}) (interface{}, error) { return c.ToFloat64(v) }, myInt(1)},
+		{"string", func(v interface{}) (interface{}, error) { return c.ToString(v) }, []byte("abc")},
+		{"stringer", func(v interface{}) (interface{}, error) { return c.ToString(v) }, String("abc")},
+		{"duration", func(v interface{}) (interface{}, error) { return c.ToDuration(v) }, time.Second},
+		{"time", func(v interface{}) (interface{}, error) { return c.ToTime(v) }, &now},
+	}
+	for _, test := range allowed {
+		if _, err := test.fn(test.src); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+
+	rejected := []struct {
+		name string
+		fn   func(interface{}) (interface{}, error)
+		src  interface{}
+	}{
+		{"bool", func(v interface{}) (interface{}, error) { return c.ToBool(v) }, 1},
+		{"int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, "1"},
+		{"int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, time.Second},
+		{"int64", func(v interface{}) (interface{}, error) { return c.ToInt64(v) }, now},
+		{"uint64", func(v interface{}) (interface{}, error) { return c.ToUint64(v) }, false},
+		{"float64", func(v interface{}) (interface{}, error) { return c.ToFloat64(v) }, "1.5"},
+		{"string", func(v interface{}) (interface{}, error) { return c.ToString(v) }, time.Second},
+		{"duration", func(v interface{}) (interface{}, error) { return c.ToDuration(v) }, "1s"},
+		{"duration", func(v interface{}) (interface{}, error) { return c.ToDuration(v) }, 1000},
+		{"time", func(v interface{}) (interface{}, error) { return c.ToTime(v) }, 1234567890},
+		{"time", func(v interface{}) (interface{}, error) { return c.ToTime(v) }, "2009-02-13T23:31:30Z"},
+	}
+	for _, test := range rejected {
+		if _, err := test.fn(test.src); err == nil {
+			t.Errorf("%s: expect an error for %T, but got nil", test.name, test.src)
+		}
+	}
+
+	if v, err := c.ToInt64(strictInt(123)); err != nil {
+		t.Error(err)
+	} else if v != 123 {
+		t.Errorf("expect %d, but got %d", 123, v)
+	}
+
+	var s string
+	if err := c.Set(&s, 123); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+
+	var mi myInt
+	if err := c.Set(reflect.ValueOf(&mi), "1"); err == nil {
+		t.Error("expect an error, but got nil")
+	} else if err = c.Set(reflect.ValueOf(&mi), uint8(1)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// TagName is the name of the struct tag used by Decode and ToMap,
+// whose value has the format as follow:
+//
+//	cast:"name,option1,option2,..."
+//
+// name is the key of the field, which is the field name if empty,
+// and the field is ignored if it is "-". The options are as follow:
+//
+//	omitempty: ignore the field if the value is empty
+//	squash: squash the fields of the struct into the parent struct
+//	remain: receive all the keys not bound to the other fields, which must be a map
+//	layout=LAYOUT: the layout to parse the string to time.Time, which may be repeated
+//	tz=LOCATION: the location of time.Time, such as "Asia/Shanghai"
+//	unit=UNIT: the unit of the number to time.Duration, such as "ns", "ms", "s", "m"
+//	sep=SEP: the separator to split the string into a slice or map
+//	base=BASE: the base to parse the string to an integer, such as 2, 8, 16
+//
+// The options layout, tz, unit, sep and base are applied to the nested values
+// of the field, such as the elements of the slice. And the option value
+// cannot contain ",".
+//
+// The embedded struct, or the pointer to struct, without the tag name
+// is squashed like encoding/json, and the fields of the parent struct hide
+// those of the embedded struct with the same key. The nil embedded pointer
+// is allocated only when one of its fields is set.
+const TagName = "cast"
+
+// DefaultTagName is the name of the struct tag used by Decode, whose value
+// is converted and set to the field if the key is missing from the source
+// or the value is omitted by omitempty. For example,
+//
+//	Timeout time.Duration `default:"30s"`
+//	Tags    []string      `default:"a,b,c"`
+const DefaultTagName = "default"
+
+// structField is the information of a struct field parsed from its tag.
+type structField struct {
+	reflect.StructField
+
+	key       string
+	omitEmpty bool
+	options   []Option
+
+	defaultValue string
+	hasDefault   bool
+
+	squash bool // Squash the fields of the embedded struct into the parent.
+	remain bool // Receive all the keys not bound to the other fields.
+}
+
+// converter returns the converter to convert the value of the field.
+func (f *structField) converter(c *Converter) *Converter {
+	if len(f.options) == 0 {
+		return c
+	}
+
+	// The options only replace the fields, so a shallow copy is enough.
+	nc := *c
+	for _, option := range f.options {
+		option(&nc)
+	}
+	return &nc
+}
+
+type structInfo struct {
+	fields []structField
+	err    error
+}
+
+var structInfos sync.Map // map[reflect.Type]structInfo
+
+// getStructFields returns the exported fields of the struct type t,
+// which is cached.
+func getStructFields(t reflect.Type) ([]structField, error) {
+	if v, ok := structInfos.Load(t); ok {
+		info := v.(structInfo)
+		return info.fields, info.err
+	}
+
+	fields, err := parseStructFields(t)
+	structInfos.Store(t, structInfo{fields: fields, err: err})
+	return fields, err
+}
+
+func parseStructFields(t reflect.Type) (fields []structField, err error) {
+	var depths []int
+	if fields, depths, err = collectStructFields(t, nil, 0, nil); err != nil {
+		return nil, err
+	}
+
+	// The shallower field hides the deeper fields with the same key,
+	// and the first one wins if they are at the same depth.
+	mindepths := make(map[string]int, len(fields))
+	for i, field := range fields {
+		key := field.key
+		if field.remain {
+			key = "\x00remain"
+		}
+
+		if depth, ok := mindepths[key]; !ok || depths[i] < depth {
+			mindepths[key] = depths[i]
+		}
+	}
+
+	_fields := fields[:0]
+	for i, field := range fields {
+		key := field.key
+		if field.remain {
+			key = "\x00remain"
+		}
+
+		if depth, ok := mindepths[key]; ok && depth == depths[i] {
+			delete(mindepths, key)
+			_fields = append(_fields, field)
+		}
+	}
+
+	return _fields, nil
+}
+
+// collectStructFields collects the fields of the struct type t recursively,
+// which squashes the embedded structs.
+func collectStructFields(t reflect.Type, index []int, depth int, visited []reflect.Type) (
+	fields []structField, depths []int, err error) {
+	for _, v := range visited {
+		if v == t {
+			return
+		}
+	}
+	visited = append(visited, t)
+
+	for i, _len := 0, t.NumField(); i < _len; i++ {
+		field := t.Field(i)
+		field.Index = append(index[:len(index):len(index)], i)
+
+		sf := structField{StructField: field, key: field.Name}
+		sf.defaultValue, sf.hasDefault = field.Tag.Lookup(DefaultTagName)
+
+		tag, hastag := field.Tag.Lookup(TagName)
+		if tag == "-" {
+			continue
+		} else if hastag {
+			if err = sf.parseTag(tag); err != nil {
+				err = fmt.Errorf("invalid tag of field %s.%s: %w", t.String(), field.Name, err)
+				return
+			}
+		}
+
+		ftype := field.Type
+		if ftype.Kind() == reflect.Pointer {
+			ftype = ftype.Elem()
+		}
+
+		// The embedded struct without the tag name is squashed like encoding/json.
+		if field.Anonymous && !sf.squash && !sf.remain && (!hastag || strings.HasPrefix(tag, ",")) {
+			sf.squash = isSquashable(ftype) && (field.IsExported() || field.Type.Kind() != reflect.Pointer)
+		}
+
+		if sf.squash {
+			if !isSquashable(ftype) || (!field.IsExported() && field.Type.Kind() == reflect.Pointer) {
+				err = fmt.Errorf("invalid tag of field %s.%s: cannot squash %s", t.String(), field.Name, field.Type.String())
+				return
+			}
+
+			_fields, _depths, _err := collectStructFields(ftype, field.Index, depth+1, visited)
+			if _err != nil {
+				return nil, nil, _err
+			}
+
+			fields = append(fields, _fields...)
+			depths = append(depths, _depths...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if sf.remain && field.Type.Kind() != reflect.Map {
+			err = fmt.Errorf("invalid tag of field %s.%s: remain requires a map, but got %s", t.String(), field.Name, field.Type.String())
+			return
+		}
+
+		fields = append(fields, sf)
+		depths = append(depths, depth)
+	}
+
+	return
+}
+
+// isSquashable reports whether the fields of the struct type t
+// can be squashed into the parent struct.
+func isSquashable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	return !isSetterType(reflect.PointerTo(t))
+}
+
+func (f *structField) parseTag(tag string) (err error) {
+	var layouts []string
+	name, opts, _ := strings.Cut(tag, ",")
+	if name = strings.TrimSpace(name); name != "" {
+		f.key = name
+	}
+
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt = strings.TrimSpace(opt); opt == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "omitempty":
+			f.omitEmpty = true
+
+		case "squash":
+			f.squash = true
+
+		case "remain":
+			f.remain = true
+
+		case "layout":
+			layouts = append(layouts, value)
+
+		case "tz":
+			var loc *time.Location
+			if loc, err = time.LoadLocation(value); err != nil {
+				return
+			}
+			f.options = append(f.options, func(c *Converter) { c.Location = loc })
+
+		case "unit":
+			var unit time.Duration
+			if unit, err = time.ParseDuration("1" + value); err != nil {
+				return fmt.Errorf("invalid unit '%s'", value)
+			}
+			f.options = append(f.options, WithDurationUnit(unit))
+
+		case "sep":
+			if value == "" {
+				return fmt.Errorf("empty separator")
+			}
+			f.options = append(f.options, WithSeparator(value))
+
+		case "base":
+			var base int
+			if base, err = strconv.Atoi(value); err != nil || base == 1 || base < 0 || base > 36 {
+				return fmt.Errorf("invalid base '%s'", value)
+			}
+			f.options = append(f.options, WithIntBase(base))
+
+		default:
+			return fmt.Errorf("unknown option '%s'", key)
+		}
+	}
+
+	if len(layouts) > 0 {
+		f.options = append(f.options, func(c *Converter) { c.Layouts = layouts })
+	}
+
+	return
+}
+
+// fieldByIndex returns the nested field of the struct value v by index.
+//
+// If an embedded pointer is nil, allocate it if alloc is true,
+// or else return false.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty, which is the same as
+// the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// KeyMatching is the mode to match the source keys with the keys
+// of the struct fields when decoding a struct.
+type KeyMatching uint8
+
+const (
+	// KeyMatchExact matches the keys exactly.
+	KeyMatchExact KeyMatching = iota
+
+	// KeyMatchFold matches the keys case-insensitively,
+	// such as "UserName" and "username".
+	KeyMatchFold
+
+	// KeyMatchNormalize matches the keys case-insensitively and ignores
+	// the characters "_" and "-", so that the keys in snake_case, kebab-case,
+	// camelCase and PascalCase match each other, such as "user_name",
+	// "user-name", "userName" and "UserName".
+	KeyMatchNormalize
+)
+
+// WithKeyMatching returns an option to set the mode to match the keys
+// when decoding a struct.
+func WithKeyMatching(mode KeyMatching) Option {
+	return func(c *Converter) { c.KeyMatching = mode }
+}
+
+// WithFieldKeys returns an option to set the function to return
+// the additional candidate keys of a struct field when decoding a struct.
+func WithFieldKeys(f func(field reflect.StructField) []string) Option {
+	return func(c *Converter) { c.FieldKeys = f }
+}
+
+func (m KeyMatching) normalize(key string) string {
+	switch m {
+	case KeyMatchFold:
+		return strings.ToLower(key)
+
+	case KeyMatchNormalize:
+		var b strings.Builder
+		b.Grow(len(key))
+		for _, r := range key {
+			switch r {
+			case '_', '-':
+			default:
+				b.WriteRune(unicode.ToLower(r))
+			}
+		}
+		return b.String()
+
+	default:
+		return key
+	}
+}
+
+// keyMatcher is used to find the value of a struct field in the source map.
+type keyMatcher struct {
+	values     map[string]interface{}
+	normalized map[string]string // the normalized key -> the source key
+}
+
+func (c *Converter) newKeyMatcher(m map[string]interface{}) keyMatcher {
+	km := keyMatcher{values: m}
+	if c.KeyMatching == KeyMatchExact || len(m) == 0 {
+		return km
+	}
+
+	// Sort the keys so that the ambiguous keys are matched deterministically.
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	km.normalized = make(map[string]string, len(m))
+	for _, key := range keys {
+		nkey := c.KeyMatching.normalize(key)
+		if _, ok := km.normalized[nkey]; !ok {
+			km.normalized[nkey] = key
+		}
+	}
+
+	return km
+}
+
+// match returns the source key and value of the field. If missing,
+// return the key of the field and false.
+//
+// The candidate keys are the key of the field and the keys returned by
+// c.FieldKeys, which are matched exactly first and then by c.KeyMatching.
+func (m keyMatcher) match(c *Converter, field *structField) (key string, value interface{}, ok bool) {
+	keys := []string{field.key}
+	if c.FieldKeys != nil {
+		keys = append(keys, c.FieldKeys(field.StructField)...)
+	}
+
+	for _, key := range keys {
+		if value, ok = m.values[key]; ok {
+			return key, value, true
+		}
+	}
+
+	if m.normalized != nil {
+		for _, key := range keys {
+			if skey, ok := m.normalized[c.KeyMatching.normalize(key)]; ok {
+				return skey, m.values[skey], true
+			}
+		}
+	}
+
+	return field.key, nil, false
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleTagName() {
+	type Config struct {
+		Name     string        `cast:"name"`
+		Birthday time.Time     `cast:"birthday,layout=2006/01/02,tz=Asia/Shanghai"`
+		Timeout  time.Duration `cast:"timeout,unit=s"`
+		Tags     []string      `cast:"tags,sep=;"`
+		Mode     uint32        `cast:"mode,base=8"`
+		Port     uint16        `cast:"port,omitempty"`
+		Ignore   string        `cast:"-"`
+	}
+
+	config := Config{Port: 80}
+	err := Decode(&config, map[string]interface{}{
+		"name":     "app",
+		"birthday": "2000/01/02",
+		"timeout":  30,
+		"tags":     "a; b; c",
+		"mode":     "755",
+		"port":     "",
+		"Ignore":   "abc",
+	})
+
+	fmt.Println(err)
+	fmt.Println(config.Name, config.Birthday, config.Timeout)
+	fmt.Println(config.Tags, config.Mode, config.Port, config.Ignore == "")
+
+	fmt.Println(ToStringMapString(struct {
+		Name string `cast:"name"`
+		Port int    `cast:"port,omitempty"`
+		Addr string `cast:"-"`
+	}{Name: "app", Addr: "127.0.0.1"}))
+
+	// Output:
+	// <nil>
+	// app 2000-01-02 00:00:00 +0800 CST 30s
+	// [a b c] 493 80 true
+	// map[name:app] <nil>
+}
+
+func TestTagOptions(t *testing.T) {
+	type S struct {
+		Times []time.Time              `cast:",layout=2006-01-02,layout=2006/01/02"`
+		Units map[string]time.Duration `cast:",unit=us,sep=|"`
+		Hexes []int64                  `cast:",base=16"`
+	}
+
+	var s S
+	err := Decode(&s, map[string]interface{}{
+		"Times": []string{"2000-01-02", "2000/01/03"},
+		"Units": "a=1|b=2.5",
+		"Hexes": "ff,10",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Times) != 2 || s.Times[0].Day() != 2 || s.Times[1].Day() != 3 {
+		t.Errorf("unexpected times %v", s.Times)
+	}
+	if expect := map[string]time.Duration{"a": time.Microsecond, "b": 2500}; !reflect.DeepEqual(s.Units, expect) {
+		t.Errorf("expect %v, but got %v", expect, s.Units)
+	}
+	if expect := []int64{255, 16}; !reflect.DeepEqual(s.Hexes, expect) {
+		t.Errorf("expect %v, but got %v", expect, s.Hexes)
+	}
+}
+
+func TestInvalidTag(t *testing.T) {
+	tests := []interface{}{
+		&struct {
+			V int `cast:",base=1"`
+		}{},
+		&struct {
+			V time.Time `cast:",tz=Unknown/Zone"`
+		}{},
+		&struct {
+			V time.Duration `cast:",unit=x"`
+		}{},
+		&struct {
+			V []int `cast:",sep="`
+		}{},
+		&struct {
+			V int `cast:",unknown"`
+		}{},
+	}
+
+	for _, dst := range tests {
+		if err := Decode(dst, map[string]interface{}{"V": 1}); err == nil {
+			t.Errorf("%T: expect an error, but got nil", dst)
+		}
+	}
+}
+
+func TestDurationUnitAndIntBase(t *testing.T) {
+	c := NewConverter(WithDurationUnit(time.Second), WithIntBase(16))
+
+	if v, err := c.ToDuration(3); err != nil {
+		t.Error(err)
+	} else if v != 3*time.Second {
+		t.Errorf("expect %s, but got %s", 3*time.Second, v)
+	}
+
+	if v, err := c.ToDuration(1.5); err != nil {
+		t.Error(err)
+	} else if v != 1500*time.Millisecond {
+		t.Errorf("expect %s, but got %s", 1500*time.Millisecond, v)
+	}
+
+	if v, err := c.ToInt64(2 * time.Second); err != nil {
+		t.Error(err)
+	} else if v != 2 {
+		t.Errorf("expect %d, but got %d", 2, v)
+	}
+
+	if v, err := c.ToFloat64(1500 * time.Millisecond); err != nil {
+		t.Error(err)
+	} else if v != 1.5 {
+		t.Errorf("expect %v, but got %v", 1.5, v)
+	}
+
+	if v, err := c.ToInt64("ff"); err != nil {
+		t.Error(err)
+	} else if v != 255 {
+		t.Errorf("expect %d, but got %d", 255, v)
+	}
+
+	if _, err := c.ToUint64("1.5"); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+}
+
+func ExampleDefaultTagName() {
+	type Server struct {
+		Host string `default:"127.0.0.1"`
+		Port uint16 `default:"80"`
+	}
+
+	type Config struct {
+		Timeout time.Duration `default:"30s"`
+		Tags    []string      `default:"a,b,c"`
+		Retry   *int          `default:"3"`
+		Start   time.Time     `cast:",layout=2006-01-02" default:"2000-01-02"`
+		Level   string        `cast:",omitempty" default:"info"`
+		Server  Server
+	}
+
+	var config Config
+	err := Decode(&config, map[string]interface{}{
+		"Level":  "",
+		"Server": map[string]interface{}{"Port": 8080},
+	})
+
+	fmt.Println(err)
+	fmt.Println(config.Timeout, config.Tags, *config.Retry, config.Start.Format(time.DateOnly), config.Level)
+	fmt.Println(config.Server.Host, config.Server.Port)
+
+	// The defaults of the nested struct are set even if it is missing.
+	config = Config{}
+	fmt.Println(Decode(&config, nil), config.Server)
+
+	// Output:
+	// <nil>
+	// 30s [a b c] 3 2000-01-02 info
+	// 127.0.0.1 8080
+	// <nil> {127.0.0.1 80}
+}
+
+func TestInvalidDefault(t *testing.T) {
+	var s struct {
+		Port int `default:"abc"`
+	}
+
+	err := Decode(&s, map[string]interface{}{"Port": 80})
+	if err != nil {
+		t.Error(err)
+	} else if s.Port != 80 {
+		t.Errorf("expect %d, but got %d", 80, s.Port)
+	}
+
+	if err := Decode(&s, nil); err == nil {
+		t.Error("expect an error, but got nil")
+	} else if ce, ok := err.(*ConversionError); !ok || ce.Path != "Port" || ce.Value != "abc" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func ExampleKeyMatching() {
+	type User struct {
+		UserName  string
+		UserAge   int
+		UserEmail string
+		HomePage  string
+	}
+
+	src := map[string]interface{}{
+		"user_name":  "Aaron",
+		"user-age":   18,
+		"userEmail":  "aaron@example.com",
+		"HOME_PAGE!": "http://example.com",
+	}
+
+	var user User
+	c := NewConverter(WithKeyMatching(KeyMatchNormalize))
+	fmt.Println(c.Decode(&user, src))
+	fmt.Println(user.UserName, user.UserAge, user.UserEmail, user.HomePage == "")
+
+	// Use the user-supplied candidate keys.
+	user = User{}
+	c = c.With(WithFieldKeys(func(field reflect.StructField) []string {
+		if field.Name == "HomePage" {
+			return []string{"HOME_PAGE!"}
+		}
+		return nil
+	}))
+	fmt.Println(c.Decode(&user, src))
+	fmt.Println(user.UserName, user.UserAge, user.UserEmail, user.HomePage)
+
+	// Output:
+	// <nil>
+	// Aaron 18 aaron@example.com true
+	// <nil>
+	// Aaron 18 aaron@example.com http://example.com
+}
+
+func TestKeyMatching(t *testing.T) {
+	type S struct {
+		Name string `cast:"name"`
+	}
+
+	tests := []struct {
+		mode   KeyMatching
+		src    map[string]interface{}
+		expect string
+	}{
+		{KeyMatchExact, map[string]interface{}{"Name": "a"}, ""},
+		{KeyMatchExact, map[string]interface{}{"name": "a"}, "a"},
+		{KeyMatchFold, map[string]interface{}{"NAME": "a"}, "a"},
+		{KeyMatchFold, map[string]interface{}{"na_me": "a"}, ""},
+		{KeyMatchFold, map[string]interface{}{"name": "a", "Name": "b"}, "a"}, // Exact first
+		{KeyMatchFold, map[string]interface{}{"NAME": "a", "Name": "b"}, "a"}, // Sorted
+		{KeyMatchNormalize, map[string]interface{}{"N-a_ME": "a"}, "a"},
+	}
+
+	for i, test := range tests {
+		var s S
+		var md DecodeMetadata
+		c := NewConverter(WithKeyMatching(test.mode))
+		if err := c.DecodeWithMetadata(&s, test.src, &md); err != nil {
+			t.Errorf("%d: %v", i, err)
+		} else if s.Name != test.expect {
+			t.Errorf("%d: expect '%s', but got '%s'", i, test.expect, s.Name)
+		} else if test.expect != "" && len(md.Unused) != len(test.src)-1 {
+			t.Errorf("%d: unexpected unused keys %v", i, md.Unused)
+		}
+	}
+}
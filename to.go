@@ -0,0 +1,41 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+// To is equal to ToWith[T](DefaultConverter, src).
+func To[T any](src interface{}) (dst T, err error) {
+	return ToWith[T](DefaultConverter, src)
+}
+
+// ToWith converts src to a value of the type T by the converter c.
+//
+// It is the same as c.Set(&dst, src), so T supports all the types
+// that Set supports, such as:
+//
+//	~bool
+//	~string
+//	~float32, ~float64
+//	~int, ~int8, ~int16, ~int32, ~int64
+//	~uint, ~uint8, ~uint16, ~uint32, ~uint64, ~uintptr
+//	time.Time
+//	time.Duration
+//	struct: => Decode
+//	slice, array, map
+//	the type whose pointer implements sql.Scanner
+//	the type whose pointer implements interface{ Set(interface{}) error }
+func ToWith[T any](c *Converter, src interface{}) (dst T, err error) {
+	err = c.Set(&dst, src)
+	return
+}
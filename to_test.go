@@ -0,0 +1,65 @@
+// Copyright 2023 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cast
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type myInt int16
+
+func ExampleTo() {
+	fmt.Println(To[bool]("true"))
+	fmt.Println(To[int32]("123"))
+	fmt.Println(To[uint8](200))
+	fmt.Println(To[float32]("1.5"))
+	fmt.Println(To[string](456))
+	fmt.Println(To[myInt]("789")) // Named type
+	fmt.Println(To[time.Duration]("1m"))
+	fmt.Println(To[time.Time](1234567890))
+	fmt.Println(To[fmtStringer](123)) // Implement interface{ Set(interface{}) error }
+
+	// Output:
+	// true <nil>
+	// 123 <nil>
+	// 200 <nil>
+	// 1.5 <nil>
+	// 456 <nil>
+	// 789 <nil>
+	// 1m0s <nil>
+	// 2009-02-13 23:31:30 +0000 UTC <nil>
+	// 123 <nil>
+}
+
+func TestToWith(t *testing.T) {
+	c := NewConverter()
+	c.ToInt64Hook = func(src interface{}) (int64, error) { return 100, nil }
+
+	if v, err := ToWith[int8](c, "1"); err != nil {
+		t.Error(err)
+	} else if v != 100 {
+		t.Errorf("expect %d, but got %d", 100, v)
+	}
+
+	if _, err := To[int]("abc"); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+
+	if _, err := To[struct{}](1); err == nil {
+		t.Error("expect an error, but got nil")
+	}
+}